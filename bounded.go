@@ -0,0 +1,215 @@
+package set
+
+import "container/list"
+
+// EvictionPolicy selects which element a BoundedSet discards
+// when it is full and a new element is added.
+type EvictionPolicy int
+
+const (
+	// LRU evicts the least recently used element.
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently used element.
+	// Ties are broken by evicting the least recently used of them.
+	LFU
+)
+
+// BoundedOptions configures a BoundedSet.
+type BoundedOptions[Elem comparable] struct {
+	// Policy is the eviction policy. The default is LRU.
+	Policy EvictionPolicy
+	// TouchOnContains makes Contains count as a use of the element.
+	TouchOnContains bool
+	// OnEvict, if not nil, is called with every evicted element.
+	OnEvict func(Elem)
+}
+
+// BoundedSet is a set that never holds more than a fixed number of elements.
+// Adding an element to a full set evicts another one according to the
+// eviction policy. All operations run in constant time.
+type BoundedSet[Elem comparable] struct {
+	capacity int
+	opts     BoundedOptions[Elem]
+	entries  map[Elem]*boundedEntry[Elem]
+	// order holds *boundedEntry values for LRU, most recently used first.
+	// For LFU it holds *frequencyBucket values in increasing frequency.
+	order *list.List
+}
+
+type boundedEntry[Elem comparable] struct {
+	value Elem
+	// elem is the position of the entry in order (LRU)
+	// or in the items of its bucket (LFU).
+	elem *list.Element
+	// bucket is the frequency bucket holding the entry (LFU only).
+	bucket *list.Element
+}
+
+type frequencyBucket struct {
+	freq int
+	// items holds *boundedEntry values, most recently used first.
+	items *list.List
+}
+
+// NewBounded returns a new empty set holding at most capacity elements.
+// It panics if capacity is not positive.
+func NewBounded[Elem comparable](capacity int, opts BoundedOptions[Elem]) *BoundedSet[Elem] {
+	if capacity <= 0 {
+		panic("capacity of bounded set has to be positive")
+	}
+	return &BoundedSet[Elem]{
+		capacity: capacity,
+		opts:     opts,
+		entries:  make(map[Elem]*boundedEntry[Elem], capacity),
+		order:    list.New(),
+	}
+}
+
+// Add adds elements to a set, evicting elements as needed to stay
+// within capacity. Adding an element that is already present counts
+// as a use of it. Add returns the evicted elements in eviction order.
+// An element added and evicted by the same call is included.
+func (s *BoundedSet[Elem]) Add(v ...Elem) []Elem {
+	var evicted []Elem
+	for _, v := range v {
+		if v != v {
+			panic("element in set has to be equal to itself")
+		}
+		if e, ok := s.entries[v]; ok {
+			s.touch(e)
+			continue
+		}
+		if len(s.entries) == s.capacity {
+			evicted = append(evicted, s.evict())
+		}
+		s.insert(v)
+	}
+	return evicted
+}
+
+// Remove removes elements from a set.
+// Elements that are not present are ignored.
+func (s *BoundedSet[Elem]) Remove(v ...Elem) {
+	for _, v := range v {
+		if e, ok := s.entries[v]; ok {
+			s.unlink(e)
+			delete(s.entries, v)
+		}
+	}
+}
+
+// Contains reports whether v is in the set.
+// If TouchOnContains is set, a hit counts as a use of v.
+func (s *BoundedSet[Elem]) Contains(v Elem) bool {
+	e, ok := s.entries[v]
+	if ok && s.opts.TouchOnContains {
+		s.touch(e)
+	}
+	return ok
+}
+
+// Len returns the number of elements in s.
+func (s *BoundedSet[Elem]) Len() int {
+	return len(s.entries)
+}
+
+// Cap returns the maximum number of elements s can hold.
+func (s *BoundedSet[Elem]) Cap() int {
+	return s.capacity
+}
+
+// Clear removes all elements from s, leaving it empty.
+// The removed elements are not reported as evicted.
+func (s *BoundedSet[Elem]) Clear() {
+	s.entries = make(map[Elem]*boundedEntry[Elem], s.capacity)
+	s.order.Init()
+}
+
+// Do calls f on every element in the set s,
+// stopping if f returns false.
+// f should not change s.
+// f will be called on values in an indeterminate order.
+func (s *BoundedSet[Elem]) Do(f func(Elem) bool) {
+	for k := range s.entries {
+		if !f(k) {
+			break
+		}
+	}
+}
+
+// ToSlice returns the elements in the set s as a slice.
+// The values will be in an indeterminate order.
+func (s *BoundedSet[Elem]) ToSlice() []Elem {
+	r := make([]Elem, 0, len(s.entries))
+	for k := range s.entries {
+		r = append(r, k)
+	}
+	return r
+}
+
+// ToSet returns the elements in the set s as a Set.
+func (s *BoundedSet[Elem]) ToSet() Set[Elem] {
+	r := WithCap[Elem](len(s.entries))
+	for k := range s.entries {
+		r.m[k] = struct{}{}
+	}
+	return r
+}
+
+func (s *BoundedSet[Elem]) insert(v Elem) {
+	e := &boundedEntry[Elem]{value: v}
+	s.entries[v] = e
+	if s.opts.Policy != LFU {
+		e.elem = s.order.PushFront(e)
+		return
+	}
+	front := s.order.Front()
+	if front == nil || front.Value.(*frequencyBucket).freq != 1 {
+		front = s.order.PushFront(&frequencyBucket{freq: 1, items: list.New()})
+	}
+	e.bucket = front
+	e.elem = front.Value.(*frequencyBucket).items.PushFront(e)
+}
+
+func (s *BoundedSet[Elem]) touch(e *boundedEntry[Elem]) {
+	if s.opts.Policy != LFU {
+		s.order.MoveToFront(e.elem)
+		return
+	}
+	cur := e.bucket.Value.(*frequencyBucket)
+	next := e.bucket.Next()
+	if next == nil || next.Value.(*frequencyBucket).freq != cur.freq+1 {
+		next = s.order.InsertAfter(&frequencyBucket{freq: cur.freq + 1, items: list.New()}, e.bucket)
+	}
+	s.unlink(e)
+	e.bucket = next
+	e.elem = next.Value.(*frequencyBucket).items.PushFront(e)
+}
+
+func (s *BoundedSet[Elem]) unlink(e *boundedEntry[Elem]) {
+	if s.opts.Policy != LFU {
+		s.order.Remove(e.elem)
+		return
+	}
+	b := e.bucket.Value.(*frequencyBucket)
+	b.items.Remove(e.elem)
+	if b.items.Len() == 0 {
+		s.order.Remove(e.bucket)
+	}
+}
+
+func (s *BoundedSet[Elem]) evict() Elem {
+	var victim *list.Element
+	if s.opts.Policy != LFU {
+		victim = s.order.Back()
+	} else {
+		victim = s.order.Front().Value.(*frequencyBucket).items.Back()
+	}
+	e := victim.Value.(*boundedEntry[Elem])
+	s.unlink(e)
+	delete(s.entries, e.value)
+	if s.opts.OnEvict != nil {
+		s.opts.OnEvict(e.value)
+	}
+	return e.value
+}
@@ -0,0 +1,191 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestBoundedSetAdd(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		capacity    int
+		opts        BoundedOptions[int]
+		ops         func(s *BoundedSet[int]) []int
+		want        Set[int]
+		wantEvicted []int
+	}{
+		"below capacity": {
+			capacity: 3,
+			ops: func(s *BoundedSet[int]) []int {
+				return s.Add(1, 2)
+			},
+			want:        Of(1, 2),
+			wantEvicted: nil,
+		},
+		"lru evicts oldest": {
+			capacity: 2,
+			ops: func(s *BoundedSet[int]) []int {
+				return s.Add(1, 2, 3, 4)
+			},
+			want:        Of(3, 4),
+			wantEvicted: []int{1, 2},
+		},
+		"lru re-add counts as use": {
+			capacity: 2,
+			ops: func(s *BoundedSet[int]) []int {
+				s.Add(1, 2, 1)
+				return s.Add(3)
+			},
+			want:        Of(1, 3),
+			wantEvicted: []int{2},
+		},
+		"lru contains without touch": {
+			capacity: 2,
+			ops: func(s *BoundedSet[int]) []int {
+				s.Add(1, 2)
+				s.Contains(1)
+				return s.Add(3)
+			},
+			want:        Of(2, 3),
+			wantEvicted: []int{1},
+		},
+		"lru contains with touch": {
+			capacity: 2,
+			opts:     BoundedOptions[int]{TouchOnContains: true},
+			ops: func(s *BoundedSet[int]) []int {
+				s.Add(1, 2)
+				s.Contains(1)
+				return s.Add(3)
+			},
+			want:        Of(1, 3),
+			wantEvicted: []int{2},
+		},
+		"lfu evicts least frequent": {
+			capacity: 3,
+			opts:     BoundedOptions[int]{Policy: LFU},
+			ops: func(s *BoundedSet[int]) []int {
+				s.Add(1, 2, 3, 1, 1, 3)
+				return s.Add(4, 5)
+			},
+			want:        Of(1, 3, 5),
+			wantEvicted: []int{2, 4},
+		},
+		"lfu ties broken by recency": {
+			capacity: 3,
+			opts:     BoundedOptions[int]{Policy: LFU},
+			ops: func(s *BoundedSet[int]) []int {
+				s.Add(1, 2, 3, 2, 1)
+				return s.Add(4)
+			},
+			want:        Of(1, 2, 4),
+			wantEvicted: []int{3},
+		},
+		"lfu contains with touch": {
+			capacity: 2,
+			opts:     BoundedOptions[int]{Policy: LFU, TouchOnContains: true},
+			ops: func(s *BoundedSet[int]) []int {
+				s.Add(1, 2)
+				s.Contains(1)
+				return s.Add(3)
+			},
+			want:        Of(1, 3),
+			wantEvicted: []int{2},
+		},
+		"remove frees room": {
+			capacity: 2,
+			opts:     BoundedOptions[int]{Policy: LFU},
+			ops: func(s *BoundedSet[int]) []int {
+				s.Add(1, 2)
+				s.Remove(1, 5)
+				return s.Add(3)
+			},
+			want:        Of(2, 3),
+			wantEvicted: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			var fromCallback []int
+			tt.opts.OnEvict = func(v int) {
+				fromCallback = append(fromCallback, v)
+			}
+			s := NewBounded(tt.capacity, tt.opts)
+			evicted := tt.ops(s)
+			if got := s.ToSet(); !tt.want.Equal(got) {
+				t.Fatalf("got %v, want %v", got.ToSlice(), tt.want.ToSlice())
+			}
+			if !slices.Equal(evicted, tt.wantEvicted) {
+				t.Fatalf("evicted: got %v, want %v", evicted, tt.wantEvicted)
+			}
+			if s.Len() > s.Cap() {
+				t.Fatalf("len %v exceeds cap %v", s.Len(), s.Cap())
+			}
+			if !slices.Equal(fromCallback, tt.wantEvicted) {
+				t.Fatalf("callback: got %v, want %v", fromCallback, tt.wantEvicted)
+			}
+		})
+	}
+}
+
+func TestBoundedSetClear(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []EvictionPolicy{LRU, LFU} {
+		s := NewBounded(2, BoundedOptions[int]{Policy: policy})
+		s.Add(1, 2)
+		s.Clear()
+		if s.Len() != 0 {
+			t.Fatalf("policy %v: got len %v, want 0", policy, s.Len())
+		}
+		if evicted := s.Add(3, 4); len(evicted) != 0 {
+			t.Fatalf("policy %v: got evicted %v, want none", policy, evicted)
+		}
+	}
+}
+
+func TestBoundedSetAddWithNaN(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "element in set has to be equal to itself" {
+			t.Fatalf("got %v, want %v", r, "element in set has to be equal to itself")
+		}
+	}()
+	NewBounded(2, BoundedOptions[float64]{}).Add(math.NaN())
+}
+
+func BenchmarkBoundedSetAdd(b *testing.B) {
+	for _, policy := range []EvictionPolicy{LRU, LFU} {
+		for _, size := range []int{1 << 8, 1 << 12, 1 << 16} {
+			b.Run(fmt.Sprintf("policy=%d/size=%d", policy, size), func(b *testing.B) {
+				s := NewBounded(size, BoundedOptions[int]{Policy: policy})
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					s.Add(i)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkBoundedSetContains(b *testing.B) {
+	for _, policy := range []EvictionPolicy{LRU, LFU} {
+		for _, size := range []int{1 << 8, 1 << 12, 1 << 16} {
+			b.Run(fmt.Sprintf("policy=%d/size=%d", policy, size), func(b *testing.B) {
+				s := NewBounded(size, BoundedOptions[int]{Policy: policy, TouchOnContains: true})
+				for i := 0; i < size; i++ {
+					s.Add(i)
+				}
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					s.Contains(i % size)
+				}
+			})
+		}
+	}
+}
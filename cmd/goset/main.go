@@ -0,0 +1,280 @@
+// Command goset performs set operations on lists of strings.
+//
+// Usage:
+//
+//	goset command [flags] [file ...]
+//
+// The commands are:
+//
+//	union      print the elements that are in any input
+//	intersect  print the elements that are in every input
+//	diff       print the elements of the first input that are in no other input
+//	symdiff    print the elements that are in an odd number of inputs
+//	subset     exit with status 0 if the first input is a subset of the second, 1 otherwise
+//	count      print the number of distinct elements in the union of the inputs
+//
+// A file named "-", or no file at all, reads standard input.
+// Gzip-compressed input is detected and decompressed automatically.
+// Inputs hold one element per line by default; see the -format flag
+// for CSV and JSON input. Output is one element per line.
+//
+// The exit status is 0 on success, 1 if subset finds that the first input
+// is not a subset of the second, and 2 on error.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	set "github.com/tacomeet/go-set"
+)
+
+const (
+	exitOK       = 0
+	exitNotFound = 1
+	exitError    = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type command struct {
+	// minInputs and maxInputs bound the number of inputs; 0 means no limit.
+	minInputs, maxInputs int
+	run                  func(inputs []set.Set[string], w *output) int
+}
+
+var commands = map[string]command{
+	"union": {minInputs: 1, run: func(inputs []set.Set[string], w *output) int {
+		r := set.Set[string]{}
+		for i := range inputs {
+			r.AddSet(&inputs[i])
+		}
+		return w.elems(&r)
+	}},
+	"intersect": {minInputs: 1, run: func(inputs []set.Set[string], w *output) int {
+		r := inputs[0]
+		for i := range inputs[1:] {
+			r = set.Intersect(&r, &inputs[i+1])
+		}
+		return w.elems(&r)
+	}},
+	"diff": {minInputs: 1, run: func(inputs []set.Set[string], w *output) int {
+		r := inputs[0]
+		for i := range inputs[1:] {
+			r.RemoveSet(&inputs[i+1])
+		}
+		return w.elems(&r)
+	}},
+	"symdiff": {minInputs: 1, run: func(inputs []set.Set[string], w *output) int {
+		e := set.Leaf[string](&inputs[0])
+		for i := range inputs[1:] {
+			e = e.Xor(set.Leaf[string](&inputs[i+1]))
+		}
+		return w.elems(e)
+	}},
+	"subset": {minInputs: 2, maxInputs: 2, run: func(inputs []set.Set[string], w *output) int {
+		if inputs[1].ContainsAll(&inputs[0]) {
+			return exitOK
+		}
+		return exitNotFound
+	}},
+	"count": {minInputs: 1, run: func(inputs []set.Set[string], w *output) int {
+		r := set.Set[string]{}
+		for i := range inputs {
+			r.AddSet(&inputs[i])
+		}
+		fmt.Fprintln(w.w, r.Len())
+		return exitOK
+	}},
+}
+
+type options struct {
+	format string
+	column int
+	header bool
+	sort   bool
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: goset command [flags] [file ...]")
+		return exitError
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "goset: unknown command %q\n", args[0])
+		return exitError
+	}
+
+	var opts options
+	fs := flag.NewFlagSet("goset "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.format, "format", "lines", "input format: lines, csv or json")
+	fs.IntVar(&opts.column, "column", 1, "CSV column to read, starting at 1")
+	fs.BoolVar(&opts.header, "header", false, "skip the first CSV record")
+	fs.BoolVar(&opts.sort, "sort", true, "sort the output")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitError
+	}
+	if opts.column < 1 {
+		fmt.Fprintf(stderr, "goset: invalid column %d\n", opts.column)
+		return exitError
+	}
+
+	names := fs.Args()
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+	if len(names) < cmd.minInputs || cmd.maxInputs > 0 && len(names) > cmd.maxInputs {
+		fmt.Fprintf(stderr, "goset %s: wrong number of inputs: %d\n", args[0], len(names))
+		return exitError
+	}
+
+	inputs := make([]set.Set[string], len(names))
+	for i, name := range names {
+		s, err := readInput(name, stdin, opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "goset: %v\n", err)
+			return exitError
+		}
+		inputs[i] = s
+	}
+
+	w := &output{w: bufio.NewWriter(stdout), sort: opts.sort}
+	status := cmd.run(inputs, w)
+	if err := w.w.Flush(); err != nil {
+		fmt.Fprintf(stderr, "goset: %v\n", err)
+		return exitError
+	}
+	return status
+}
+
+func readInput(name string, stdin io.Reader, opts options) (set.Set[string], error) {
+	r := stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return set.Set[string]{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+	s, err := read(r, opts)
+	if err != nil && name != "-" {
+		err = fmt.Errorf("%s: %w", name, err)
+	}
+	return s, err
+}
+
+// read reads a set from r, decompressing it if it is gzip-compressed.
+func read(r io.Reader, opts options) (set.Set[string], error) {
+	s := set.Set[string]{}
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return s, err
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+
+	switch opts.format {
+	case "lines":
+		sc := bufio.NewScanner(br)
+		sc.Buffer(nil, 1<<20)
+		for sc.Scan() {
+			if line := bytes.TrimSuffix(sc.Bytes(), []byte("\r")); len(line) > 0 {
+				s.Add(string(line))
+			}
+		}
+		return s, sc.Err()
+	case "csv":
+		cr := csv.NewReader(br)
+		cr.FieldsPerRecord = -1
+		for first := true; ; first = false {
+			rec, err := cr.Read()
+			if err == io.EOF {
+				return s, nil
+			}
+			if err != nil {
+				return s, err
+			}
+			if first && opts.header {
+				continue
+			}
+			if len(rec) < opts.column {
+				line, _ := cr.FieldPos(0)
+				return s, fmt.Errorf("line %d: no column %d", line, opts.column)
+			}
+			s.Add(rec[opts.column-1])
+		}
+	case "json":
+		dec := json.NewDecoder(br)
+		dec.UseNumber()
+		if err := expectDelim(dec, '['); err != nil {
+			return s, err
+		}
+		for dec.More() {
+			var v any
+			if err := dec.Decode(&v); err != nil {
+				return s, err
+			}
+			switch v := v.(type) {
+			case string:
+				s.Add(v)
+			case json.Number:
+				s.Add(v.String())
+			default:
+				return s, fmt.Errorf("unsupported JSON element %v", v)
+			}
+		}
+		return s, expectDelim(dec, ']')
+	default:
+		return s, fmt.Errorf("unknown format %q", opts.format)
+	}
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return errors.New("input is not a JSON array")
+	}
+	return nil
+}
+
+type output struct {
+	w    *bufio.Writer
+	sort bool
+}
+
+// elems writes the elements of s, one per line.
+func (o *output) elems(s set.Interface[string]) int {
+	r := make([]string, 0, s.Len())
+	s.Do(func(v string) bool {
+		r = append(r, v)
+		return true
+	})
+	if o.sort {
+		slices.Sort(r)
+	}
+	for _, v := range r {
+		o.w.WriteString(v)
+		o.w.WriteByte('\n')
+	}
+	return exitOK
+}
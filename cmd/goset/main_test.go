@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte("b\nc\nd\n"))
+	zw.Close()
+
+	files := map[string]string{
+		"a.txt":    "a\nb\nc\n",
+		"b.txt":    "b\r\nc\r\n\r\nd\r\n",
+		"c.txt":    "c\nx\n",
+		"sub.txt":  "b\nc\n",
+		"ids.csv":  "id,name\nc,cat\na,ann\n",
+		"ids.json": `["a", "b", 10000000000000001]`,
+		"b.txt.gz": gz.String(),
+		"bad.json": `{"a": 1}`,
+	}
+	dir := t.TempDir()
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		args       []string
+		stdin      string
+		want       string
+		wantStatus int
+	}{
+		"union": {
+			args: []string{"union", "a.txt", "b.txt", "c.txt"},
+			want: "a\nb\nc\nd\nx\n",
+		},
+		"intersect": {
+			args: []string{"intersect", "a.txt", "b.txt", "c.txt"},
+			want: "c\n",
+		},
+		"diff": {
+			args: []string{"diff", "a.txt", "b.txt"},
+			want: "a\n",
+		},
+		"symdiff": {
+			args: []string{"symdiff", "a.txt", "b.txt"},
+			want: "a\nd\n",
+		},
+		"symdiff of three": {
+			args: []string{"symdiff", "a.txt", "b.txt", "c.txt"},
+			want: "a\nc\nd\nx\n",
+		},
+		"subset": {
+			args:       []string{"subset", "sub.txt", "a.txt"},
+			wantStatus: exitOK,
+		},
+		"not subset": {
+			args:       []string{"subset", "a.txt", "sub.txt"},
+			wantStatus: exitNotFound,
+		},
+		"count": {
+			args: []string{"count", "a.txt", "b.txt"},
+			want: "4\n",
+		},
+		"stdin": {
+			args:  []string{"intersect", "-", "a.txt"},
+			stdin: "c\nz\na\n",
+			want:  "a\nc\n",
+		},
+		"no files reads stdin": {
+			args:  []string{"count"},
+			stdin: "z\nz\ny\n",
+			want:  "2\n",
+		},
+		"gzip": {
+			args: []string{"diff", "b.txt.gz", "a.txt"},
+			want: "d\n",
+		},
+		"csv column": {
+			args: []string{"union", "-format=csv", "-header", "-column=2", "ids.csv"},
+			want: "ann\ncat\n",
+		},
+		"json": {
+			args: []string{"union", "-format=json", "ids.json"},
+			want: "10000000000000001\na\nb\n",
+		},
+		"unsorted": {
+			args:  []string{"union", "-sort=false"},
+			stdin: "only\n",
+			want:  "only\n",
+		},
+		"unknown command": {
+			args:       []string{"join", "a.txt"},
+			wantStatus: exitError,
+		},
+		"missing file": {
+			args:       []string{"union", "missing.txt"},
+			wantStatus: exitError,
+		},
+		"wrong number of inputs": {
+			args:       []string{"subset", "a.txt"},
+			wantStatus: exitError,
+		},
+		"invalid json": {
+			args:       []string{"union", "-format=json", "bad.json"},
+			wantStatus: exitError,
+		},
+		"missing csv column": {
+			args:       []string{"union", "-format=csv", "-column=3", "ids.csv"},
+			wantStatus: exitError,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				if _, ok := files[arg]; ok || arg == "missing.txt" {
+					arg = filepath.Join(dir, arg)
+				}
+				args[i] = arg
+			}
+			var stdout, stderr bytes.Buffer
+			status := run(args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.wantStatus {
+				t.Fatalf("status: got %v, want %v (stderr %q)", status, tt.wantStatus, stderr.String())
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
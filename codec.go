@@ -0,0 +1,99 @@
+package set
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/tacomeet/go-set/internal/wire"
+	"golang.org/x/exp/constraints"
+)
+
+// Codec converts set elements to bytes and back, so that sets can be
+// stored and transmitted.
+type Codec[Elem any] interface {
+	// Append appends the encoding of v to b and returns the extended buffer.
+	Append(b []byte, v Elem) ([]byte, error)
+	// Decode decodes an element from the whole of b.
+	// It must not retain b.
+	Decode(b []byte) (Elem, error)
+}
+
+// ErrInvalidEncoding is returned by codecs for bytes that don't encode
+// an element.
+var ErrInvalidEncoding = wire.ErrInvalidEncoding
+
+// ErrDuplicate is returned by strict decoders for encoded sets that
+// contain an element more than once.
+var ErrDuplicate = wire.ErrDuplicate
+
+// StringCodec encodes strings as their UTF-8 bytes. Strings that aren't
+// valid UTF-8 can't be encoded.
+type StringCodec struct{}
+
+func (StringCodec) Append(b []byte, v string) ([]byte, error) {
+	if !utf8.ValidString(v) {
+		return b, ErrInvalidEncoding
+	}
+	return append(b, v...), nil
+}
+
+func (StringCodec) Decode(b []byte) (string, error) {
+	if !utf8.Valid(b) {
+		return "", ErrInvalidEncoding
+	}
+	return string(b), nil
+}
+
+// VarintCodec encodes integers as varints, zig-zag encoding
+// signed types so that small negative numbers stay short.
+type VarintCodec[Elem constraints.Integer] struct{}
+
+func (VarintCodec[Elem]) Append(b []byte, v Elem) ([]byte, error) {
+	if wire.IsSigned[Elem]() {
+		return binary.AppendVarint(b, int64(v)), nil
+	}
+	return binary.AppendUvarint(b, uint64(v)), nil
+}
+
+func (VarintCodec[Elem]) Decode(b []byte) (Elem, error) {
+	var v Elem
+	var n int
+	if wire.IsSigned[Elem]() {
+		var x int64
+		x, n = binary.Varint(b)
+		v = Elem(x)
+		if int64(v) != x {
+			return 0, ErrInvalidEncoding
+		}
+	} else {
+		var x uint64
+		x, n = binary.Uvarint(b)
+		v = Elem(x)
+		if uint64(v) != x {
+			return 0, ErrInvalidEncoding
+		}
+	}
+	if n <= 0 || n != len(b) {
+		return 0, ErrInvalidEncoding
+	}
+	return v, nil
+}
+
+// JSONCodec encodes elements with encoding/json. It works for any
+// element type that survives a round trip through JSON.
+type JSONCodec[Elem any] struct{}
+
+func (JSONCodec[Elem]) Append(b []byte, v Elem) ([]byte, error) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return b, err
+	}
+	return append(b, j...), nil
+}
+
+func (JSONCodec[Elem]) Decode(b []byte) (Elem, error) {
+	var v Elem
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
@@ -0,0 +1,82 @@
+package set
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func testCodecRoundTrip[Elem comparable](t *testing.T, c Codec[Elem], values ...Elem) {
+	t.Helper()
+	for _, v := range values {
+		prefix := []byte("prefix")
+		b, err := c.Append(prefix, v)
+		if err != nil {
+			t.Fatalf("append %v: %v", v, err)
+		}
+		if !bytes.HasPrefix(b, []byte("prefix")) {
+			t.Fatalf("append %v: got %q, want the prefix kept", v, b)
+		}
+		got, err := c.Decode(b[len("prefix"):])
+		if err != nil {
+			t.Fatalf("decode %v: %v", v, err)
+		}
+		if got != v {
+			t.Fatalf("got %v, want %v", got, v)
+		}
+	}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testCodecRoundTrip[string](t, StringCodec{}, "", "a", "日本語")
+	testCodecRoundTrip[int](t, VarintCodec[int]{}, 0, 1, -1, math.MaxInt, math.MinInt)
+	testCodecRoundTrip[int8](t, VarintCodec[int8]{}, 0, math.MaxInt8, math.MinInt8)
+	testCodecRoundTrip[uint64](t, VarintCodec[uint64]{}, 0, 1, math.MaxUint64)
+	testCodecRoundTrip[float64](t, JSONCodec[float64]{}, 0, 1.5, -2)
+	type point struct{ X, Y int }
+	testCodecRoundTrip[point](t, JSONCodec[point]{}, point{}, point{1, 2})
+}
+
+func TestCodecDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func() error{
+		"invalid utf-8": func() error {
+			_, err := StringCodec{}.Decode([]byte{0xff})
+			return err
+		},
+		"encode invalid utf-8": func() error {
+			_, err := StringCodec{}.Append(nil, "\xff")
+			return err
+		},
+		"empty varint": func() error {
+			_, err := VarintCodec[int]{}.Decode(nil)
+			return err
+		},
+		"trailing bytes": func() error {
+			_, err := VarintCodec[int]{}.Decode([]byte{1, 2})
+			return err
+		},
+		"overflow": func() error {
+			_, err := VarintCodec[int8]{}.Decode([]byte{0x80, 0x02})
+			return err
+		},
+		"unsigned overflow": func() error {
+			_, err := VarintCodec[uint8]{}.Decode([]byte{0x80, 0x02})
+			return err
+		},
+	}
+
+	for name, decode := range tests {
+		decode := decode
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := decode(); err != ErrInvalidEncoding {
+				t.Fatalf("got %v, want %v", err, ErrInvalidEncoding)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package set
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Delta is the change that turns one set into another.
+// Added and Removed are disjoint.
+type Delta[Elem comparable] struct {
+	Added   Set[Elem]
+	Removed Set[Elem]
+}
+
+// Diff returns the delta that turns old into new.
+func Diff[Elem comparable](old, new Set[Elem]) Delta[Elem] {
+	d := Delta[Elem]{
+		Added:   WithCap[Elem](0),
+		Removed: WithCap[Elem](0),
+	}
+	for k := range new.m {
+		if _, ok := old.m[k]; !ok {
+			d.Added.m[k] = struct{}{}
+		}
+	}
+	for k := range old.m {
+		if _, ok := new.m[k]; !ok {
+			d.Removed.m[k] = struct{}{}
+		}
+	}
+	return d
+}
+
+// Apply applies the delta d to s.
+func (s *Set[Elem]) Apply(d Delta[Elem]) {
+	s.RemoveSet(&d.Removed)
+	s.AddSet(&d.Added)
+}
+
+// IsEmpty reports whether d makes no change.
+func (d Delta[Elem]) IsEmpty() bool {
+	return d.Added.Len() == 0 && d.Removed.Len() == 0
+}
+
+// Invert returns the delta that undoes d.
+func (d Delta[Elem]) Invert() Delta[Elem] {
+	return Delta[Elem]{
+		Added:   d.Removed.Clone(),
+		Removed: d.Added.Clone(),
+	}
+}
+
+// Compose returns the delta equivalent to applying d and then other.
+func (d Delta[Elem]) Compose(other Delta[Elem]) Delta[Elem] {
+	r := Delta[Elem]{
+		Added:   Difference(&d.Added, &other.Removed),
+		Removed: Difference(&d.Removed, &other.Added),
+	}
+	for k := range other.Added.m {
+		if _, ok := d.Removed.m[k]; !ok {
+			r.Added.m[k] = struct{}{}
+		}
+	}
+	for k := range other.Removed.m {
+		if _, ok := d.Added.m[k]; !ok {
+			r.Removed.m[k] = struct{}{}
+		}
+	}
+	return r
+}
+
+type jsonDelta[Elem comparable] struct {
+	Added   []Elem `json:"added"`
+	Removed []Elem `json:"removed"`
+}
+
+// MarshalJSON encodes d as an object with "added" and "removed" arrays.
+// The elements of each array will be in an indeterminate order.
+func (d Delta[Elem]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonDelta[Elem]{
+		Added:   d.Added.ToSlice(),
+		Removed: d.Removed.ToSlice(),
+	})
+}
+
+// UnmarshalJSON decodes d from the format written by MarshalJSON.
+func (d *Delta[Elem]) UnmarshalJSON(data []byte) error {
+	var j jsonDelta[Elem]
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	added, removed := Of(j.Added...), Of(j.Removed...)
+	if added.ContainsAny(&removed) {
+		return errors.New("set: delta adds and removes the same element")
+	}
+	d.Added, d.Removed = added, removed
+	return nil
+}
@@ -0,0 +1,158 @@
+package set
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		old         Set[int]
+		new         Set[int]
+		wantAdded   Set[int]
+		wantRemoved Set[int]
+	}{
+		"initialization and empty": {
+			old:         Set[int]{},
+			new:         Set[int]{},
+			wantAdded:   Of[int](),
+			wantRemoved: Of[int](),
+		},
+		"same elements": {
+			old:         Of(1, 2),
+			new:         Of(1, 2),
+			wantAdded:   Of[int](),
+			wantRemoved: Of[int](),
+		},
+		"added and removed": {
+			old:         Of(1, 2, 3),
+			new:         Of(2, 3, 4, 5),
+			wantAdded:   Of(4, 5),
+			wantRemoved: Of(1),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			d := Diff(tt.old, tt.new)
+			if !tt.wantAdded.Equal(d.Added) {
+				t.Fatalf("added: got %v, want %v", d.Added.ToSlice(), tt.wantAdded.ToSlice())
+			}
+			if !tt.wantRemoved.Equal(d.Removed) {
+				t.Fatalf("removed: got %v, want %v", d.Removed.ToSlice(), tt.wantRemoved.ToSlice())
+			}
+			got := tt.old.Clone()
+			got.Apply(d)
+			if !tt.new.Equal(got) {
+				t.Fatalf("apply: got %v, want %v", got.ToSlice(), tt.new.ToSlice())
+			}
+			got.Apply(d.Invert())
+			if !tt.old.Equal(got) {
+				t.Fatalf("apply inverse: got %v, want %v", got.ToSlice(), tt.old.ToSlice())
+			}
+		})
+	}
+}
+
+func TestDeltaCompose(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		a, b, c Set[int]
+	}{
+		"independent changes": {
+			a: Of(1, 2),
+			b: Of(1, 2, 3),
+			c: Of(2, 3, 4),
+		},
+		"added then removed": {
+			a: Of(1),
+			b: Of(1, 2),
+			c: Of(1),
+		},
+		"removed then added": {
+			a: Of(1, 2),
+			b: Of(1),
+			c: Of(1, 2),
+		},
+		"empty": {
+			a: Set[int]{},
+			b: Set[int]{},
+			c: Set[int]{},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got := Diff(tt.a, tt.b).Compose(Diff(tt.b, tt.c))
+			want := Diff(tt.a, tt.c)
+			if !want.Added.Equal(got.Added) || !want.Removed.Equal(got.Removed) {
+				t.Fatalf("got +%v -%v, want +%v -%v",
+					got.Added.ToSlice(), got.Removed.ToSlice(), want.Added.ToSlice(), want.Removed.ToSlice())
+			}
+		})
+	}
+}
+
+func TestDeltaJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data    string
+		want    Delta[int]
+		wantErr bool
+	}{
+		"empty": {
+			data: `{"added":[],"removed":[]}`,
+			want: Delta[int]{Added: Of[int](), Removed: Of[int]()},
+		},
+		"missing fields": {
+			data: `{}`,
+			want: Delta[int]{Added: Of[int](), Removed: Of[int]()},
+		},
+		"added and removed": {
+			data: `{"added":[1,2],"removed":[3]}`,
+			want: Delta[int]{Added: Of(1, 2), Removed: Of(3)},
+		},
+		"overlapping": {
+			data:    `{"added":[1],"removed":[1]}`,
+			wantErr: true,
+		},
+		"malformed": {
+			data:    `{"added":1}`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			var got Delta[int]
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error: got %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !tt.want.Added.Equal(got.Added) || !tt.want.Removed.Equal(got.Removed) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var again Delta[int]
+			if err := json.Unmarshal(b, &again); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Added.Equal(again.Added) || !got.Removed.Equal(again.Removed) {
+				t.Fatalf("round trip: got %s", b)
+			}
+		})
+	}
+}
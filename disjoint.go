@@ -0,0 +1,143 @@
+package set
+
+// DisjointSets partitions elements into disjoint sets, merging them on
+// request (a union-find structure). It uses path compression and union
+// by rank, so its operations take nearly constant amortized time.
+//
+// Elements that have not been added are treated as sets of their own.
+// The zero value is an empty partition.
+type DisjointSets[Elem comparable] struct {
+	index map[Elem]int
+	elems []Elem
+	// parent, rank and size are indexed like elems. size is only
+	// maintained for roots.
+	parent []int
+	rank   []uint8
+	size   []int
+	count  int
+}
+
+// MakeSet adds each v as a set of its own.
+// Elements that are already present are left unchanged.
+func (d *DisjointSets[Elem]) MakeSet(v ...Elem) {
+	for _, v := range v {
+		d.id(v)
+	}
+}
+
+// id returns the index of v, adding it as a set of its own if needed.
+func (d *DisjointSets[Elem]) id(v Elem) int {
+	if i, ok := d.index[v]; ok {
+		return i
+	}
+	if v != v {
+		panic("element in set has to be equal to itself")
+	}
+	if d.index == nil {
+		d.index = map[Elem]int{}
+	}
+	i := len(d.elems)
+	d.index[v] = i
+	d.elems = append(d.elems, v)
+	d.parent = append(d.parent, i)
+	d.rank = append(d.rank, 0)
+	d.size = append(d.size, 1)
+	d.count++
+	return i
+}
+
+// root returns the index of the representative of the set holding
+// index i, pointing every index on the way directly at it.
+func (d *DisjointSets[Elem]) root(i int) int {
+	r := i
+	for d.parent[r] != r {
+		r = d.parent[r]
+	}
+	for d.parent[i] != r {
+		d.parent[i], i = r, d.parent[i]
+	}
+	return r
+}
+
+// Union merges the sets holding a and b, adding either of them if it is
+// not present, and reports whether they were in different sets.
+func (d *DisjointSets[Elem]) Union(a, b Elem) bool {
+	ra, rb := d.root(d.id(a)), d.root(d.id(b))
+	if ra == rb {
+		return false
+	}
+	if d.rank[ra] < d.rank[rb] {
+		ra, rb = rb, ra
+	}
+	d.parent[rb] = ra
+	d.size[ra] += d.size[rb]
+	if d.rank[ra] == d.rank[rb] {
+		d.rank[ra]++
+	}
+	d.count--
+	return true
+}
+
+// Find returns the representative of the set holding v.
+// Two elements are in the same set if and only if they have the same
+// representative, which may change when sets are merged.
+func (d *DisjointSets[Elem]) Find(v Elem) Elem {
+	i, ok := d.index[v]
+	if !ok {
+		return v
+	}
+	return d.elems[d.root(i)]
+}
+
+// Connected reports whether a and b are in the same set.
+func (d *DisjointSets[Elem]) Connected(a, b Elem) bool {
+	if a == b {
+		return true
+	}
+	i, ok := d.index[a]
+	if !ok {
+		return false
+	}
+	j, ok := d.index[b]
+	if !ok {
+		return false
+	}
+	return d.root(i) == d.root(j)
+}
+
+// SetSize returns the number of elements in the set holding v.
+func (d *DisjointSets[Elem]) SetSize(v Elem) int {
+	i, ok := d.index[v]
+	if !ok {
+		return 1
+	}
+	return d.size[d.root(i)]
+}
+
+// Len returns the number of elements that have been added.
+func (d *DisjointSets[Elem]) Len() int {
+	return len(d.elems)
+}
+
+// Count returns the number of disjoint sets.
+func (d *DisjointSets[Elem]) Count() int {
+	return d.count
+}
+
+// Groups returns the disjoint sets, in the order their first element
+// was added.
+func (d *DisjointSets[Elem]) Groups() []Set[Elem] {
+	groups := make([]Set[Elem], 0, d.count)
+	// group holds, for each root, one more than the position of its
+	// set in groups.
+	group := make([]int, len(d.elems))
+	for i, v := range d.elems {
+		r := d.root(i)
+		if group[r] == 0 {
+			groups = append(groups, WithCap[Elem](d.size[r]))
+			group[r] = len(groups)
+		}
+		groups[group[r]-1].m[v] = struct{}{}
+	}
+	return groups
+}
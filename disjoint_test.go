@@ -0,0 +1,192 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDisjointSets(t *testing.T) {
+	t.Parallel()
+
+	type pair struct{ a, b string }
+	tests := map[string]struct {
+		make       []string
+		unions     []pair
+		wantGroups []Set[string]
+		wantMerged []bool
+	}{
+		"empty": {
+			wantGroups: []Set[string]{},
+		},
+		"singletons": {
+			make:       []string{"a", "b", "a"},
+			wantGroups: []Set[string]{Of("a"), Of("b")},
+		},
+		"union adds elements": {
+			unions:     []pair{{"a", "b"}, {"c", "d"}},
+			wantGroups: []Set[string]{Of("a", "b"), Of("c", "d")},
+			wantMerged: []bool{true, true},
+		},
+		"transitive": {
+			make:       []string{"x"},
+			unions:     []pair{{"a", "b"}, {"c", "b"}, {"d", "a"}, {"a", "c"}},
+			wantGroups: []Set[string]{Of("x"), Of("a", "b", "c", "d")},
+			wantMerged: []bool{true, true, true, false},
+		},
+		"self union": {
+			unions:     []pair{{"a", "a"}},
+			wantGroups: []Set[string]{Of("a")},
+			wantMerged: []bool{false},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var d DisjointSets[string]
+			d.MakeSet(tt.make...)
+			for i, p := range tt.unions {
+				if got := d.Union(p.a, p.b); got != tt.wantMerged[i] {
+					t.Fatalf("union %v: got %v, want %v", p, got, tt.wantMerged[i])
+				}
+			}
+
+			got := d.Groups()
+			if len(got) != len(tt.wantGroups) || d.Count() != len(tt.wantGroups) {
+				t.Fatalf("got %v (count %v), want %v", got, d.Count(), tt.wantGroups)
+			}
+			n := 0
+			for i := range got {
+				if !got[i].Equal(tt.wantGroups[i]) {
+					t.Fatalf("group %d: got %v, want %v", i, got[i], tt.wantGroups[i])
+				}
+				n += got[i].Len()
+				for _, v := range got[i].ToSlice() {
+					if size := d.SetSize(v); size != got[i].Len() {
+						t.Fatalf("size of %v: got %v, want %v", v, size, got[i].Len())
+					}
+					if r := d.Find(v); !got[i].Contains(r) {
+						t.Fatalf("find %v: got %v, want an element of %v", v, r, got[i])
+					}
+					for j := range got {
+						for _, w := range got[j].ToSlice() {
+							if c := d.Connected(v, w); c != (i == j) {
+								t.Fatalf("connected %v %v: got %v, want %v", v, w, c, i == j)
+							}
+						}
+					}
+				}
+			}
+			if d.Len() != n {
+				t.Fatalf("len: got %v, want %v", d.Len(), n)
+			}
+		})
+	}
+}
+
+func TestDisjointSetsAbsent(t *testing.T) {
+	t.Parallel()
+
+	var d DisjointSets[int]
+	d.Union(1, 2)
+	if got := d.Find(3); got != 3 {
+		t.Fatalf("find: got %v, want %v", got, 3)
+	}
+	if got := d.SetSize(3); got != 1 {
+		t.Fatalf("size: got %v, want %v", got, 1)
+	}
+	if d.Connected(1, 3) || d.Connected(3, 4) || !d.Connected(3, 3) {
+		t.Fatalf("absent elements must only be connected to themselves")
+	}
+	if d.Len() != 2 {
+		t.Fatalf("len: got %v, want %v", d.Len(), 2)
+	}
+}
+
+func TestDisjointSetsRandom(t *testing.T) {
+	t.Parallel()
+
+	const n = 1000
+	r := rand.New(rand.NewSource(1))
+	var d DisjointSets[int]
+	// label is a naive partition to check d against.
+	label := make([]int, n)
+	for i := range label {
+		label[i] = i
+	}
+	d.MakeSet(0)
+	for i := 0; i < 700; i++ {
+		a, b := r.Intn(n), r.Intn(n)
+		d.Union(a, b)
+		if la, lb := label[a], label[b]; la != lb {
+			for j := range label {
+				if label[j] == lb {
+					label[j] = la
+				}
+			}
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		a, b := r.Intn(n), r.Intn(n)
+		if got, want := d.Connected(a, b), label[a] == label[b]; got != want {
+			t.Fatalf("connected %v %v: got %v, want %v", a, b, got, want)
+		}
+	}
+}
+
+func TestDisjointSetsWithNaN(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "element in set has to be equal to itself" {
+			t.Fatalf("got %v, want %v", r, "element in set has to be equal to itself")
+		}
+	}()
+	var d DisjointSets[float64]
+	d.Union(1, math.NaN())
+}
+
+func BenchmarkDisjointSets(b *testing.B) {
+	for _, n := range []int{1 << 10, 1 << 20} {
+		r := rand.New(rand.NewSource(1))
+		pairs := make([][2]int, n)
+		for i := range pairs {
+			pairs[i] = [2]int{r.Intn(n), r.Intn(n)}
+		}
+		b.Run(fmt.Sprintf("Union/n=%d", n), func(b *testing.B) {
+			var d DisjointSets[int]
+			for i := 0; i < b.N; i++ {
+				if i%n == 0 {
+					d = DisjointSets[int]{}
+				}
+				p := pairs[i%n]
+				d.Union(p[0], p[1])
+			}
+		})
+		b.Run(fmt.Sprintf("Connected/n=%d", n), func(b *testing.B) {
+			var d DisjointSets[int]
+			for _, p := range pairs[:n/2] {
+				d.Union(p[0], p[1])
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p := pairs[i%n]
+				d.Connected(p[0], p[1])
+			}
+		})
+		b.Run(fmt.Sprintf("Groups/n=%d", n), func(b *testing.B) {
+			var d DisjointSets[int]
+			for _, p := range pairs {
+				d.Union(p[0], p[1])
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d.Groups()
+			}
+		})
+	}
+}
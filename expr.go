@@ -0,0 +1,245 @@
+package set
+
+import "sort"
+
+type exprOp int
+
+const (
+	opLeaf exprOp = iota
+	opUnion
+	opIntersect
+	opMinus
+	opXor
+)
+
+// Expr is a lazily evaluated expression over sets.
+// Building an expression does no work; Contains answers membership
+// without materializing intermediate results, and Eval produces a Set
+// only when asked. Intersections are evaluated smallest operand first:
+// Contains checks the operands in order of their sizes when the
+// intersection was built, and Do, Len and Eval iterate the operand that
+// is smallest when they are called.
+//
+// Expr implements Interface, so an expression can be passed to the
+// package level operations or used as an operand of another expression.
+// The leaves are read when the expression is used, not when it is built.
+type Expr[Elem comparable] struct {
+	op   exprOp
+	leaf Interface[Elem]
+	// args are the operands of op. Minus and Xor have exactly two.
+	args []*Expr[Elem]
+}
+
+// Leaf returns an expression consisting of the set s.
+func Leaf[Elem comparable](s Interface[Elem]) *Expr[Elem] {
+	return &Expr[Elem]{op: opLeaf, leaf: s}
+}
+
+// Union returns the expression for the union of e and others.
+func (e *Expr[Elem]) Union(others ...*Expr[Elem]) *Expr[Elem] {
+	return e.nary(opUnion, others)
+}
+
+// Intersect returns the expression for the intersection of e and others.
+func (e *Expr[Elem]) Intersect(others ...*Expr[Elem]) *Expr[Elem] {
+	return e.nary(opIntersect, others)
+}
+
+// Minus returns the expression for the elements of e that are not in other.
+func (e *Expr[Elem]) Minus(other *Expr[Elem]) *Expr[Elem] {
+	return &Expr[Elem]{op: opMinus, args: []*Expr[Elem]{e, other}}
+}
+
+// Xor returns the expression for the elements that are in exactly one
+// of e and other.
+func (e *Expr[Elem]) Xor(other *Expr[Elem]) *Expr[Elem] {
+	return &Expr[Elem]{op: opXor, args: []*Expr[Elem]{e, other}}
+}
+
+// nary flattens nested operations of the same kind into a single node.
+func (e *Expr[Elem]) nary(op exprOp, others []*Expr[Elem]) *Expr[Elem] {
+	r := &Expr[Elem]{op: op}
+	for _, x := range append([]*Expr[Elem]{e}, others...) {
+		if x.op == op {
+			r.args = append(r.args, x.args...)
+		} else {
+			r.args = append(r.args, x)
+		}
+	}
+	if op == opIntersect {
+		sortBySize(r.args)
+	}
+	return r
+}
+
+// Contains reports whether v is in the result of e.
+func (e *Expr[Elem]) Contains(v Elem) bool {
+	switch e.op {
+	case opLeaf:
+		return e.leaf.Contains(v)
+	case opUnion:
+		for _, x := range e.args {
+			if x.Contains(v) {
+				return true
+			}
+		}
+		return false
+	case opIntersect:
+		for _, x := range e.args {
+			if !x.Contains(v) {
+				return false
+			}
+		}
+		return true
+	case opMinus:
+		return e.args[0].Contains(v) && !e.args[1].Contains(v)
+	default:
+		return e.args[0].Contains(v) != e.args[1].Contains(v)
+	}
+}
+
+// Do calls f on every element in the result of e,
+// stopping if f returns false.
+// f will be called on values in an indeterminate order.
+func (e *Expr[Elem]) Do(f func(Elem) bool) {
+	e.do(f)
+}
+
+// do is Do, reporting whether f returned false.
+func (e *Expr[Elem]) do(f func(Elem) bool) (stopped bool) {
+	visit := func(v Elem) bool {
+		if !f(v) {
+			stopped = true
+		}
+		return !stopped
+	}
+	switch e.op {
+	case opLeaf:
+		e.leaf.Do(visit)
+	case opUnion:
+		for i, x := range e.args {
+			seen := e.args[:i]
+			x.do(func(v Elem) bool {
+				for _, y := range seen {
+					if y.Contains(v) {
+						return true
+					}
+				}
+				return visit(v)
+			})
+			if stopped {
+				break
+			}
+		}
+	case opIntersect:
+		args := e.bySize()
+		args[0].do(func(v Elem) bool {
+			for _, y := range args[1:] {
+				if !y.Contains(v) {
+					return true
+				}
+			}
+			return visit(v)
+		})
+	case opMinus:
+		e.args[0].do(func(v Elem) bool {
+			if e.args[1].Contains(v) {
+				return true
+			}
+			return visit(v)
+		})
+	default:
+		for i := range e.args {
+			x, y := e.args[i], e.args[1-i]
+			x.do(func(v Elem) bool {
+				if y.Contains(v) {
+					return true
+				}
+				return visit(v)
+			})
+			if stopped {
+				break
+			}
+		}
+	}
+	return stopped
+}
+
+// Len returns the number of elements in the result of e.
+// It iterates over the result, so it takes time proportional to
+// the size of the operands.
+func (e *Expr[Elem]) Len() int {
+	n := 0
+	e.do(func(Elem) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Eval evaluates e and returns the result as a new set.
+func (e *Expr[Elem]) Eval() Set[Elem] {
+	r := WithCap[Elem](e.estimate())
+	e.do(func(v Elem) bool {
+		r.m[v] = struct{}{}
+		return true
+	})
+	return r
+}
+
+// estimate returns an upper bound of the number of elements in e.
+func (e *Expr[Elem]) estimate() int {
+	switch e.op {
+	case opLeaf:
+		// The length of an expression is computed by iterating it.
+		if x, ok := e.leaf.(*Expr[Elem]); ok {
+			return x.estimate()
+		}
+		return e.leaf.Len()
+	case opIntersect:
+		n := e.args[0].estimate()
+		for _, x := range e.args[1:] {
+			if m := x.estimate(); m < n {
+				n = m
+			}
+		}
+		return n
+	case opMinus:
+		return e.args[0].estimate()
+	default:
+		n := 0
+		for _, x := range e.args {
+			n += x.estimate()
+		}
+		return n
+	}
+}
+
+// bySize returns the operands of e ordered by their estimated size.
+func (e *Expr[Elem]) bySize() []*Expr[Elem] {
+	args := make([]*Expr[Elem], len(e.args))
+	copy(args, e.args)
+	sortBySize(args)
+	return args
+}
+
+// sortBySize sorts args by their estimated size.
+func sortBySize[Elem comparable](args []*Expr[Elem]) {
+	sizes := make([]int, len(args))
+	for i, x := range args {
+		sizes[i] = x.estimate()
+	}
+	sort.Stable(sizedExprs[Elem]{args, sizes})
+}
+
+type sizedExprs[Elem comparable] struct {
+	args  []*Expr[Elem]
+	sizes []int
+}
+
+func (s sizedExprs[Elem]) Len() int           { return len(s.args) }
+func (s sizedExprs[Elem]) Less(i, j int) bool { return s.sizes[i] < s.sizes[j] }
+func (s sizedExprs[Elem]) Swap(i, j int) {
+	s.args[i], s.args[j] = s.args[j], s.args[i]
+	s.sizes[i], s.sizes[j] = s.sizes[j], s.sizes[i]
+}
@@ -0,0 +1,160 @@
+package set
+
+import "testing"
+
+// countingSet records the number of calls to Contains.
+type countingSet struct {
+	Set[int]
+	calls int
+}
+
+func (s *countingSet) Contains(v int) bool {
+	s.calls++
+	return s.Set.Contains(v)
+}
+
+func TestExpr(t *testing.T) {
+	t.Parallel()
+
+	a, b, c, d := Of(1, 2, 3, 4), Of(3, 4, 5, 6), Of(2, 3, 4, 5, 7), Of(4)
+	la, lb, lc, ld := Leaf[int](&a), Leaf[int](&b), Leaf[int](&c), Leaf[int](&d)
+	union, intersection := Union(&a, &b), Intersect(&a, &b)
+
+	tests := map[string]struct {
+		expr *Expr[int]
+		want Set[int]
+	}{
+		"leaf": {
+			expr: la,
+			want: Of(1, 2, 3, 4),
+		},
+		"initialization and empty": {
+			expr: Leaf[int](&Set[int]{}).Union(Leaf[int](&Set[int]{})),
+			want: Of[int](),
+		},
+		"union": {
+			expr: la.Union(lb),
+			want: union,
+		},
+		"intersect": {
+			expr: la.Intersect(lb),
+			want: intersection,
+		},
+		"minus": {
+			expr: la.Minus(lb),
+			want: Of(1, 2),
+		},
+		"xor": {
+			expr: la.Xor(lb),
+			want: Of(1, 2, 5, 6),
+		},
+		"union then intersect then minus": {
+			expr: la.Union(lb).Intersect(lc).Minus(ld),
+			want: Of(2, 3, 5),
+		},
+		"nested union is flattened": {
+			expr: la.Union(lb).Union(lc.Union(ld)),
+			want: Of(1, 2, 3, 4, 5, 6, 7),
+		},
+		"intersect of three": {
+			expr: lc.Intersect(la, lb),
+			want: Of(3, 4),
+		},
+		"xor of expressions": {
+			expr: la.Minus(ld).Xor(lb.Intersect(lc)),
+			want: Of(1, 2, 4, 5),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got := tt.expr.Eval()
+			if !tt.want.Equal(got) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if n := tt.expr.Len(); n != tt.want.Len() {
+				t.Fatalf("len: got %v, want %v", n, tt.want.Len())
+			}
+			for v := 0; v < 10; v++ {
+				if got, want := tt.expr.Contains(v), tt.want.Contains(v); got != want {
+					t.Fatalf("contains %v: got %v, want %v", v, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExprDoStopped(t *testing.T) {
+	t.Parallel()
+
+	a, b := Of(1, 2, 3), Of(4, 5, 6)
+	calledTimes := 0
+	Leaf[int](&a).Union(Leaf[int](&b)).Do(func(int) bool {
+		calledTimes++
+		return calledTimes < 4
+	})
+	if calledTimes != 4 {
+		t.Fatalf("calledTimes: got %v, want %v", calledTimes, 4)
+	}
+}
+
+func TestExprIntersectOrder(t *testing.T) {
+	t.Parallel()
+
+	large := &countingSet{Set: Set[int]{}}
+	for i := 0; i < 1000; i++ {
+		large.Add(i)
+	}
+	small := &countingSet{Set: Of(1, 2000)}
+	e := Leaf[int](large).Intersect(Leaf[int](small))
+
+	if e.Contains(3000) {
+		t.Fatalf("got true, want false")
+	}
+	if large.calls != 0 || small.calls != 1 {
+		t.Fatalf("contains: got %v calls on large, %v on small, want 0 and 1", large.calls, small.calls)
+	}
+	large.calls, small.calls = 0, 0
+	if got, want := e.Eval(), Of(1); !want.Equal(got) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if large.calls != 2 {
+		t.Fatalf("eval: got %v calls on large, want 2", large.calls)
+	}
+}
+
+// iterCountingSet records the number of elements visited by Do.
+type iterCountingSet struct {
+	Set[int]
+	visits int
+}
+
+func (s *iterCountingSet) Do(f func(int) bool) {
+	s.Set.Do(func(v int) bool {
+		s.visits++
+		return f(v)
+	})
+}
+
+func TestExprIntersectContainsCost(t *testing.T) {
+	// Not parallel: AllocsPerRun measures the whole process.
+	large := &iterCountingSet{Set: Set[int]{}}
+	for i := 0; i < 1000; i++ {
+		large.Add(i)
+	}
+	small := Of(1, 2, 2000)
+	nested := Leaf[int](Leaf[int](large).Union(Leaf[int](&small)))
+	e := nested.Intersect(Leaf[int](&small))
+
+	if !e.Contains(1) || e.Contains(3) {
+		t.Fatalf("got wrong membership for %v", e.Eval())
+	}
+	large.visits = 0
+	if allocs := testing.AllocsPerRun(100, func() { e.Contains(1) }); allocs != 0 {
+		t.Fatalf("got %v allocations, want 0", allocs)
+	}
+	if large.visits != 0 {
+		t.Fatalf("got %v elements visited, want 0", large.visits)
+	}
+}
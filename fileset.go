@@ -0,0 +1,753 @@
+package set
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileOptions configures a FileSet.
+type FileOptions[Elem comparable] struct {
+	// Codec encodes the elements in the files. It is required.
+	Codec Codec[Elem]
+	// NoSync skips calling fsync after every change. Changes then
+	// survive a crash of the process but not of the machine.
+	NoSync bool
+	// CompactAfter is the number of records the log may hold before it
+	// is compacted into a snapshot. The log is only compacted once it
+	// also holds more records than the set has elements.
+	// The default is 4096; a negative value disables compaction.
+	CompactAfter int
+}
+
+// FileSet is a set that persists its changes to disk, so that it
+// survives restarts. Every change is appended to a log file as
+// checksummed records, and the log is replayed when the set is opened.
+// When the log grows, it is compacted in the background into a snapshot
+// of the elements.
+//
+// The log is stored at the path passed to OpenFileSet, and the snapshot
+// next to it with the suffix ".snapshot". If the last write to the log
+// was torn by a crash, the incomplete record is discarded when the set
+// is opened. If a write fails, the set stops accepting changes and
+// returns the error from every later change; reopen it to recover.
+//
+// A FileSet is safe for concurrent use.
+type FileSet[Elem comparable] struct {
+	path string
+	opts FileOptions[Elem]
+
+	mu      sync.Mutex
+	set     Set[Elem]
+	log     *os.File
+	records int
+	buf     []byte
+	scratch []byte
+	err     error
+	// compacting is closed when the running compaction, if any, ends.
+	compacting chan struct{}
+}
+
+const (
+	fileLogMagic      = "GOSETLG1"
+	fileSnapshotMagic = "GOSETSN1"
+
+	opAdd    byte = 1
+	opRemove byte = 2
+	opClear  byte = 3
+	// opEnd ends a snapshot. Its payload is the number of elements.
+	opEnd byte = 4
+)
+
+var (
+	// ErrCorrupt is returned when opening a FileSet whose files are
+	// damaged other than by a torn final write.
+	ErrCorrupt = errors.New("set: corrupt file")
+	// ErrClosed is returned by changes to a FileSet that has been closed.
+	ErrClosed = errors.New("set: file set is closed")
+
+	errBadRecord = errors.New("bad record")
+)
+
+// OpenFileSet opens the set stored at path, creating it if it doesn't
+// exist.
+func OpenFileSet[Elem comparable](path string, opts FileOptions[Elem]) (*FileSet[Elem], error) {
+	if opts.Codec == nil {
+		return nil, errors.New("set: FileOptions.Codec is nil")
+	}
+	if opts.CompactAfter == 0 {
+		opts.CompactAfter = 4096
+	}
+	s := &FileSet[Elem]{path: path, opts: opts}
+	if err := s.recover(); err != nil {
+		if s.log != nil {
+			s.log.Close()
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSet[Elem]) oldPath() string      { return s.path + ".old" }
+func (s *FileSet[Elem]) snapshotPath() string { return s.path + ".snapshot" }
+
+// recover loads the snapshot and replays the logs.
+func (s *FileSet[Elem]) recover() error {
+	if err := s.readSnapshot(); err != nil {
+		return err
+	}
+	// An old log is left behind by a compaction that didn't finish.
+	err := s.replay(s.oldPath(), false)
+	hasOld := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	err = s.replay(s.path, true)
+	if errors.Is(err, os.ErrNotExist) {
+		err = s.createLog()
+	}
+	if err != nil || !hasOld {
+		return err
+	}
+	// Finish the compaction, folding both logs into the snapshot.
+	if err := s.writeSnapshot(s.set.Clone()); err != nil {
+		return err
+	}
+	if err := os.Remove(s.oldPath()); err != nil {
+		return err
+	}
+	if err := s.log.Truncate(int64(len(fileLogMagic))); err != nil {
+		return err
+	}
+	if _, err := s.log.Seek(int64(len(fileLogMagic)), io.SeekStart); err != nil {
+		return err
+	}
+	s.records = 0
+	return s.sync(s.log)
+}
+
+func (s *FileSet[Elem]) readSnapshot() error {
+	f, err := os.Open(s.snapshotPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rr, err := newRecordReader(f, fileSnapshotMagic)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrCorrupt, f.Name(), err)
+	}
+	for {
+		op, payload, err := rr.next()
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrCorrupt, f.Name(), err)
+		}
+		switch op {
+		case opAdd:
+			v, err := s.opts.Codec.Decode(payload)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrCorrupt, f.Name(), err)
+			}
+			s.set.Add(v)
+		case opEnd:
+			if n, k := binary.Uvarint(payload); k <= 0 || n != uint64(s.set.Len()) {
+				return fmt.Errorf("%w: %s: wrong element count", ErrCorrupt, f.Name())
+			}
+			return nil
+		default:
+			return fmt.Errorf("%w: %s: unexpected record %d", ErrCorrupt, f.Name(), op)
+		}
+	}
+}
+
+// replay applies the records of the log at path. If open is true, the
+// log is kept open for appending, with a torn record at its end removed.
+func (s *FileSet[Elem]) replay(path string, open bool) error {
+	flag := os.O_RDONLY
+	if open {
+		flag = os.O_RDWR
+	}
+	f, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return err
+	}
+	if err := s.replayFile(f, open); err != nil {
+		f.Close()
+		return err
+	}
+	if !open {
+		return f.Close()
+	}
+	s.log = f
+	return nil
+}
+
+func (s *FileSet[Elem]) replayFile(f *os.File, open bool) error {
+	rr, err := newRecordReader(f, fileLogMagic)
+	if errors.Is(err, io.ErrUnexpectedEOF) && open {
+		// The header itself was torn; start the log again.
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := f.WriteAt([]byte(fileLogMagic), 0); err != nil {
+			return err
+		}
+		_, err = f.Seek(int64(len(fileLogMagic)), io.SeekStart)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrCorrupt, f.Name(), err)
+	}
+	n := 0
+	for {
+		op, payload, err := rr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if !rr.torn(err) {
+				return fmt.Errorf("%w: %s: %v at offset %d", ErrCorrupt, f.Name(), err, rr.off)
+			}
+			break
+		}
+		if err := s.apply(op, payload); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrCorrupt, f.Name(), err)
+		}
+		n++
+	}
+	s.records += n
+	if !open {
+		return nil
+	}
+	if rr.off < rr.size {
+		if err := f.Truncate(rr.off); err != nil {
+			return err
+		}
+	}
+	_, err = f.Seek(rr.off, io.SeekStart)
+	return err
+}
+
+func (s *FileSet[Elem]) apply(op byte, payload []byte) error {
+	switch op {
+	case opAdd, opRemove:
+		v, err := s.opts.Codec.Decode(payload)
+		if err != nil {
+			return err
+		}
+		if op == opAdd {
+			s.set.Add(v)
+		} else {
+			s.set.Remove(v)
+		}
+	case opClear:
+		s.set.Clear()
+	default:
+		return fmt.Errorf("unexpected record %d", op)
+	}
+	return nil
+}
+
+func (s *FileSet[Elem]) createLog() error {
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	s.log = f
+	if _, err := f.Write([]byte(fileLogMagic)); err != nil {
+		return err
+	}
+	if err := s.sync(f); err != nil {
+		return err
+	}
+	return s.syncDir()
+}
+
+// sync flushes f to disk unless NoSync is set.
+func (s *FileSet[Elem]) sync(f *os.File) error {
+	if s.opts.NoSync {
+		return nil
+	}
+	return f.Sync()
+}
+
+// syncDir flushes the directory holding the files unless NoSync is set,
+// so that files created or renamed in it survive a crash.
+func (s *FileSet[Elem]) syncDir() error {
+	if s.opts.NoSync {
+		return nil
+	}
+	d, err := os.Open(filepath.Dir(s.path))
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	// Not every platform can sync a directory.
+	d.Sync()
+	return nil
+}
+
+// writeSnapshot atomically replaces the snapshot with the elements of set.
+func (s *FileSet[Elem]) writeSnapshot(set Set[Elem]) error {
+	tmp := s.snapshotPath() + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	w.WriteString(fileSnapshotMagic)
+	var rec, payload []byte
+	for v := range set.m {
+		payload, err = s.opts.Codec.Append(payload[:0], v)
+		if err != nil {
+			break
+		}
+		rec = appendRecord(rec[:0], opAdd, payload)
+		w.Write(rec)
+	}
+	if err == nil {
+		w.Write(appendRecord(rec[:0], opEnd, binary.AppendUvarint(nil, uint64(set.Len()))))
+		err = w.Flush()
+	}
+	if err == nil {
+		err = s.sync(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, s.snapshotPath())
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return s.syncDir()
+}
+
+// startCompaction moves the log aside and snapshots the set in the
+// background. s.mu must be held.
+func (s *FileSet[Elem]) startCompaction() {
+	if s.compacting != nil {
+		return
+	}
+	if err := s.rotate(); err != nil {
+		s.err = err
+		return
+	}
+	done := make(chan struct{})
+	s.compacting = done
+	set := s.set.Clone()
+	go func() {
+		defer close(done)
+		err := s.writeSnapshot(set)
+		if err == nil {
+			err = os.Remove(s.oldPath())
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err != nil && s.err == nil {
+			s.err = err
+		}
+		s.compacting = nil
+	}()
+}
+
+// rotate renames the log to the old log and starts a new one.
+func (s *FileSet[Elem]) rotate() error {
+	if err := s.log.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(s.path, s.oldPath()); err != nil {
+		return err
+	}
+	s.records = 0
+	return s.createLog()
+}
+
+// commit logs a change that has already been made in memory: op applied
+// to each of elems, or a single clear. If logging fails, undo is called
+// to revert the change. s.mu must be held.
+func (s *FileSet[Elem]) commit(op byte, elems []Elem, undo func()) error {
+	if len(elems) == 0 {
+		return nil
+	}
+	s.buf = s.buf[:0]
+	n := len(elems)
+	if op == opClear {
+		s.buf = appendRecord(s.buf, opClear, nil)
+		n = 1
+	} else {
+		for _, v := range elems {
+			var err error
+			s.scratch, err = s.opts.Codec.Append(s.scratch[:0], v)
+			if err != nil {
+				undo()
+				return err
+			}
+			s.buf = appendRecord(s.buf, op, s.scratch)
+		}
+	}
+	_, err := s.log.Write(s.buf)
+	if err == nil {
+		err = s.sync(s.log)
+	}
+	if err != nil {
+		undo()
+		s.err = err
+		return err
+	}
+	s.records += n
+	if s.opts.CompactAfter > 0 && s.records >= s.opts.CompactAfter && s.records > s.set.Len() {
+		s.startCompaction()
+	}
+	return nil
+}
+
+// Add adds elements to the set.
+func (s *FileSet[Elem]) Add(v ...Elem) error {
+	for _, v := range v {
+		if v != v {
+			panic("element in set has to be equal to itself")
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	var added []Elem
+	for _, v := range v {
+		if !s.set.Contains(v) {
+			s.set.Add(v)
+			added = append(added, v)
+		}
+	}
+	return s.commit(opAdd, added, func() { s.set.Remove(added...) })
+}
+
+// AddSet adds the elements of set s2 to s.
+func (s *FileSet[Elem]) AddSet(s2 Interface[Elem]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	var added []Elem
+	s2.Do(func(v Elem) bool {
+		if !s.set.Contains(v) {
+			s.set.Add(v)
+			added = append(added, v)
+		}
+		return true
+	})
+	return s.commit(opAdd, added, func() { s.set.Remove(added...) })
+}
+
+// Remove removes elements from the set.
+// Elements that are not present are ignored.
+func (s *FileSet[Elem]) Remove(v ...Elem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	var removed []Elem
+	for _, v := range v {
+		if s.set.Contains(v) {
+			s.set.Remove(v)
+			removed = append(removed, v)
+		}
+	}
+	return s.commit(opRemove, removed, func() { s.set.Add(removed...) })
+}
+
+// RemoveSet removes the elements of set s2 from s.
+// Elements present in s2 but not s are ignored.
+func (s *FileSet[Elem]) RemoveSet(s2 Interface[Elem]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	var removed []Elem
+	s2.Do(func(v Elem) bool {
+		if s.set.Contains(v) {
+			s.set.Remove(v)
+			removed = append(removed, v)
+		}
+		return true
+	})
+	return s.commit(opRemove, removed, func() { s.set.Add(removed...) })
+}
+
+// Retain deletes any elements from s for which keep returns false.
+func (s *FileSet[Elem]) Retain(keep func(Elem) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	var removed []Elem
+	s.set.Do(func(v Elem) bool {
+		if !keep(v) {
+			removed = append(removed, v)
+		}
+		return true
+	})
+	s.set.Remove(removed...)
+	return s.commit(opRemove, removed, func() { s.set.Add(removed...) })
+}
+
+// Clear removes all elements from s, leaving it empty.
+func (s *FileSet[Elem]) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	removed := s.set.ToSlice()
+	s.set.Clear()
+	return s.commit(opClear, removed, func() { s.set.Add(removed...) })
+}
+
+// Pop removes and returns an arbitrary element from s.
+func (s *FileSet[Elem]) Pop() (Elem, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var zero Elem
+	if s.err != nil {
+		return zero, false, s.err
+	}
+	v, ok := s.set.Pop()
+	if !ok {
+		return zero, false, nil
+	}
+	if err := s.commit(opRemove, []Elem{v}, func() { s.set.Add(v) }); err != nil {
+		return zero, false, err
+	}
+	return v, true, nil
+}
+
+// Contains reports whether v is in the set.
+func (s *FileSet[Elem]) Contains(v Elem) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.Contains(v)
+}
+
+// Len returns the number of elements in s.
+func (s *FileSet[Elem]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.Len()
+}
+
+// Do calls f on every element in the set s,
+// stopping if f returns false.
+// f must not call methods on s.
+// f will be called on values in an indeterminate order.
+func (s *FileSet[Elem]) Do(f func(Elem) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set.Do(f)
+}
+
+// ToSlice returns the elements in the set s as a slice.
+// The values will be in an indeterminate order.
+func (s *FileSet[Elem]) ToSlice() []Elem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.ToSlice()
+}
+
+// Clone returns a copy of the elements of s as a Set.
+func (s *FileSet[Elem]) Clone() Set[Elem] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.Clone()
+}
+
+// Compact compacts the log into a snapshot and waits for it to finish.
+func (s *FileSet[Elem]) Compact() error {
+	s.mu.Lock()
+	for s.compacting != nil {
+		done := s.compacting
+		s.mu.Unlock()
+		<-done
+		s.mu.Lock()
+	}
+	if s.err == nil {
+		s.startCompaction()
+	}
+	done := s.compacting
+	s.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close waits for any running compaction and closes the log.
+// It returns the first error the set encountered, if any.
+func (s *FileSet[Elem]) Close() error {
+	s.mu.Lock()
+	for s.compacting != nil {
+		done := s.compacting
+		s.mu.Unlock()
+		<-done
+		s.mu.Lock()
+	}
+	defer s.mu.Unlock()
+	if s.err == ErrClosed {
+		return ErrClosed
+	}
+	err := s.log.Close()
+	if s.err != nil {
+		err = s.err
+	}
+	s.err = ErrClosed
+	return err
+}
+
+// appendRecord appends a record holding op and payload to b. A record is
+// the op byte, the payload length as a uvarint, the payload, and the
+// CRC-32C of all of them.
+func appendRecord(b []byte, op byte, payload []byte) []byte {
+	start := len(b)
+	b = append(b, op)
+	b = binary.AppendUvarint(b, uint64(len(payload)))
+	b = append(b, payload...)
+	return binary.LittleEndian.AppendUint32(b, crc32.Checksum(b[start:], crcTable))
+}
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// recordReader reads the records written by appendRecord.
+type recordReader struct {
+	f *os.File
+	r *bufio.Reader
+	// off is the offset of the next record, and end the offset just
+	// past the record that failed to read, if it is known.
+	off, end int64
+	size     int64
+	buf      []byte
+}
+
+// newRecordReader checks that f starts with magic and returns a reader
+// for the records that follow.
+func newRecordReader(f *os.File, magic string) (*recordReader, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	rr := &recordReader{f: f, r: bufio.NewReader(f), size: fi.Size()}
+	b := make([]byte, len(magic))
+	n, err := io.ReadFull(rr.r, b)
+	if string(b[:n]) != magic[:n] {
+		return nil, errors.New("wrong file type")
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	rr.off = int64(n)
+	return rr, nil
+}
+
+// next returns the next record. It returns io.EOF at the end of the
+// records, io.ErrUnexpectedEOF if the last record is cut short, and
+// errBadRecord if a record is damaged.
+func (rr *recordReader) next() (op byte, payload []byte, err error) {
+	op, err = rr.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, err := binary.ReadUvarint(rr.r)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		rr.end = -1
+		return 0, nil, errBadRecord
+	}
+	head := 1 + uvarintLen(n)
+	if n > uint64(rr.size-rr.off) {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	total := head + int(n) + 4
+	if cap(rr.buf) < total {
+		rr.buf = make([]byte, total)
+	}
+	b := rr.buf[:total]
+	b[0] = op
+	binary.PutUvarint(b[1:], n)
+	if _, err := io.ReadFull(rr.r, b[head:]); err != nil {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	if crc32.Checksum(b[:total-4], crcTable) != binary.LittleEndian.Uint32(b[total-4:]) {
+		rr.end = rr.off + int64(total)
+		return 0, nil, errBadRecord
+	}
+	rr.off += int64(total)
+	return op, b[head : head+int(n)], nil
+}
+
+// torn reports whether err, returned by next, is explained by a torn
+// final write rather than damage to the middle of the file. A damaged
+// length can also make a record run past the end of the file, so the
+// record only counts as the last one if no intact records follow its
+// start.
+func (rr *recordReader) torn(err error) bool {
+	if err != io.ErrUnexpectedEOF && (err != errBadRecord || rr.end != rr.size) {
+		return false
+	}
+	rest := make([]byte, rr.size-rr.off)
+	if _, err := rr.f.ReadAt(rest, rr.off); err != nil {
+		return false
+	}
+	for i := 1; i < len(rest); i++ {
+		if intactRecords(rest[i:]) {
+			return false
+		}
+	}
+	return true
+}
+
+// intactRecords reports whether b consists of whole records with valid
+// checksums.
+func intactRecords(b []byte) bool {
+	for len(b) > 0 {
+		n, k := binary.Uvarint(b[1:])
+		if k <= 0 || len(b) < 1+k+4 || n > uint64(len(b)-1-k-4) {
+			return false
+		}
+		total := 1 + k + int(n) + 4
+		if crc32.Checksum(b[:total-4], crcTable) != binary.LittleEndian.Uint32(b[total-4:]) {
+			return false
+		}
+		b = b[total:]
+	}
+	return true
+}
+
+func uvarintLen(x uint64) int {
+	n := 1
+	for x >= 0x80 {
+		x >>= 7
+		n++
+	}
+	return n
+}
@@ -0,0 +1,346 @@
+package set
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func openTestFileSet(t *testing.T, path string, opts FileOptions[string]) *FileSet[string] {
+	t.Helper()
+	if opts.Codec == nil {
+		opts.Codec = StringCodec{}
+	}
+	s, err := OpenFileSet(path, opts)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return s
+}
+
+func TestFileSetPersists(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		ops  func(s *FileSet[string]) error
+		want Set[string]
+	}{
+		"empty": {
+			ops:  func(s *FileSet[string]) error { return nil },
+			want: Of[string](),
+		},
+		"add and remove": {
+			ops: func(s *FileSet[string]) error {
+				if err := s.Add("a", "b", "c", "a"); err != nil {
+					return err
+				}
+				return s.Remove("b", "z")
+			},
+			want: Of("a", "c"),
+		},
+		"sets": {
+			ops: func(s *FileSet[string]) error {
+				add, remove := Of("a", "b", "c"), Of("c")
+				if err := s.AddSet(&add); err != nil {
+					return err
+				}
+				return s.RemoveSet(&remove)
+			},
+			want: Of("a", "b"),
+		},
+		"clear and retain": {
+			ops: func(s *FileSet[string]) error {
+				s.Add("a", "b")
+				if err := s.Clear(); err != nil {
+					return err
+				}
+				s.Add("c", "dd", "ee")
+				return s.Retain(func(v string) bool { return len(v) == 2 })
+			},
+			want: Of("dd", "ee"),
+		},
+		"pop": {
+			ops: func(s *FileSet[string]) error {
+				s.Add("a")
+				v, ok, err := s.Pop()
+				if v != "a" || !ok {
+					return fmt.Errorf("pop: got %v, %v, want a, true", v, ok)
+				}
+				return err
+			},
+			want: Of[string](),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "set")
+			s := openTestFileSet(t, path, FileOptions[string]{})
+			if err := tt.ops(s); err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Clone(); !got.Equal(tt.want) {
+				t.Fatalf("before reopening: got %v, want %v", got, tt.want)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+			if err := s.Add("x"); err != ErrClosed {
+				t.Fatalf("add after close: got %v, want %v", err, ErrClosed)
+			}
+
+			s = openTestFileSet(t, path, FileOptions[string]{})
+			defer s.Close()
+			if got := s.Clone(); !got.Equal(tt.want) {
+				t.Fatalf("after reopening: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// writeTestLog writes a log that adds "0" to "9" one at a time and
+// returns the state after each record along with the log's contents.
+func writeTestLog(t *testing.T, dir string) ([]Set[string], []byte) {
+	t.Helper()
+	path := filepath.Join(dir, "log")
+	s := openTestFileSet(t, path, FileOptions[string]{NoSync: true})
+	states := []Set[string]{Of[string]()}
+	for i := 0; i < 10; i++ {
+		s.Add(fmt.Sprint(i))
+		states = append(states, s.Clone())
+	}
+	s.Close()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return states, data
+}
+
+func TestFileSetTornWrite(t *testing.T) {
+	t.Parallel()
+
+	states, data := writeTestLog(t, t.TempDir())
+	// Each record holds one digit: op, length, payload and checksum.
+	const recordSize = 1 + 1 + 1 + 4
+	for n := 0; n <= len(data); n++ {
+		path := filepath.Join(t.TempDir(), "log")
+		if err := os.WriteFile(path, data[:n], 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s := openTestFileSet(t, path, FileOptions[string]{NoSync: true})
+		want := states[max(0, n-len(fileLogMagic))/recordSize]
+		if got := s.Clone(); !got.Equal(want) {
+			t.Fatalf("cut at %d: got %v, want %v", n, got, want)
+		}
+		// The torn record is dropped, so new records can be read back.
+		s.Add("new")
+		s.Close()
+		s = openTestFileSet(t, path, FileOptions[string]{NoSync: true})
+		if got := s.Len(); got != want.Len()+1 {
+			t.Fatalf("cut at %d: got %v elements after adding, want %v", n, got, want.Len()+1)
+		}
+		s.Close()
+	}
+}
+
+func TestFileSetCorrupt(t *testing.T) {
+	t.Parallel()
+
+	states, data := writeTestLog(t, t.TempDir())
+	tests := map[string]struct {
+		offset  int
+		want    Set[string]
+		wantErr error
+	}{
+		"first record": {
+			offset:  len(fileLogMagic) + 2,
+			wantErr: ErrCorrupt,
+		},
+		"length of a middle record": {
+			// The length becomes a two byte uvarint that runs past the
+			// end of the file.
+			offset:  len(fileLogMagic) + 3*(1+1+1+4) + 1,
+			wantErr: ErrCorrupt,
+		},
+		"last record": {
+			offset: len(data) - 1,
+			want:   states[9],
+		},
+		"magic": {
+			offset:  0,
+			wantErr: ErrCorrupt,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "log")
+			b := append([]byte(nil), data...)
+			b[tt.offset] ^= 0xff
+			if err := os.WriteFile(path, b, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			s, err := OpenFileSet[string](path, FileOptions[string]{Codec: StringCodec{}})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				// The damaged file is left alone.
+				if got, _ := os.ReadFile(path); !bytes.Equal(got, b) {
+					t.Fatalf("file changed from % x to % x", b, got)
+				}
+				return
+			}
+			defer s.Close()
+			if got := s.Clone(); !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileSetCompaction(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "set")
+	s := openTestFileSet(t, path, FileOptions[string]{CompactAfter: 10, NoSync: true})
+	want := Set[string]{}
+	for i := 0; i < 100; i++ {
+		v := fmt.Sprint(i % 7)
+		if i%3 == 0 {
+			s.Remove(v)
+			want.Remove(v)
+		} else {
+			s.Add(v)
+			want.Add(v)
+		}
+	}
+	if err := s.Compact(); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	s.Close()
+
+	if _, err := os.Stat(path + ".old"); !os.IsNotExist(err) {
+		t.Fatalf("old log still exists after compaction: %v", err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Size() != int64(len(fileLogMagic)) {
+		t.Fatalf("log after compaction: got %v, %v, want an empty log", fi.Size(), err)
+	}
+	s = openTestFileSet(t, path, FileOptions[string]{})
+	defer s.Close()
+	if got := s.Clone(); !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestFileSetInterruptedCompaction(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "set")
+	s := openTestFileSet(t, path, FileOptions[string]{CompactAfter: -1})
+	s.Add("a", "b", "c")
+	s.Compact()
+	s.Add("d")
+	s.Remove("a")
+	s.Close()
+
+	// Leave the log where a compaction would have moved it, with a
+	// newer log after it, as if the process died before snapshotting.
+	if err := os.Rename(path, path+".old"); err != nil {
+		t.Fatal(err)
+	}
+	s = openTestFileSet(t, path, FileOptions[string]{})
+	s.Add("e")
+	s.Close()
+
+	s = openTestFileSet(t, path, FileOptions[string]{})
+	defer s.Close()
+	if got, want := s.Clone(), Of("b", "c", "d", "e"); !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if _, err := os.Stat(path + ".old"); !os.IsNotExist(err) {
+		t.Fatalf("old log still exists after recovery: %v", err)
+	}
+}
+
+func TestFileSetConcurrent(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "set")
+	s := openTestFileSet(t, path, FileOptions[string]{CompactAfter: 50, NoSync: true})
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				v := fmt.Sprint(w, "-", i)
+				if err := s.Add(v); err != nil {
+					t.Error(err)
+					return
+				}
+				if i%2 == 0 {
+					s.Remove(v)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	s = openTestFileSet(t, path, FileOptions[string]{})
+	defer s.Close()
+	if got := s.Len(); got != 400 {
+		t.Fatalf("got %v elements, want %v", got, 400)
+	}
+}
+
+func TestOpenFileSetWithoutCodec(t *testing.T) {
+	t.Parallel()
+
+	if _, err := OpenFileSet(filepath.Join(t.TempDir(), "set"), FileOptions[int]{}); err == nil {
+		t.Fatalf("got no error, want one")
+	}
+}
+
+func TestFileSetInvalidElement(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "set")
+	s := openTestFileSet(t, path, FileOptions[string]{NoSync: true})
+	if err := s.Add("a"); err != nil {
+		t.Fatal(err)
+	}
+	// The change is refused rather than logged in a form that can't be
+	// read back.
+	if err := s.Add("b", "\xff\xfe"); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidEncoding)
+	}
+	if err := s.Add("c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s = openTestFileSet(t, path, FileOptions[string]{NoSync: true})
+	defer s.Close()
+	if got, want := s.Clone(), Of("a", "c"); !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
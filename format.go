@@ -0,0 +1,94 @@
+package set
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/tacomeet/go-set/internal/order"
+)
+
+// FormatLimit is the maximum number of elements printed by String
+// and Format before the output is truncated.
+const FormatLimit = 64
+
+// LogLimit is the maximum number of elements included by LogValue.
+const LogLimit = 32
+
+// String returns the elements of s in braces, such as "{1, 2, 3}".
+// Elements are sorted when they are numbers, strings or booleans,
+// and sorted by their printed form otherwise.
+// At most FormatLimit elements are printed.
+func (s Set[Elem]) String() string {
+	return s.format("%v", FormatLimit)
+}
+
+// GoString returns a Go expression that constructs s,
+// such as "set.Of[int](1, 2, 3)". It is used by the %#v verb.
+func (s Set[Elem]) GoString() string {
+	var b strings.Builder
+	b.WriteString("set.Of[")
+	b.WriteString(reflect.TypeOf((*Elem)(nil)).Elem().String())
+	b.WriteString("](")
+	for i, v := range s.sorted() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%#v", v)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// Format implements fmt.Formatter. The verb and flags are applied to
+// each element, so %x prints a set of integers in hexadecimal.
+// %#v prints the GoString form and %+v prints every element;
+// otherwise at most FormatLimit elements are printed.
+func (s Set[Elem]) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		io.WriteString(f, s.GoString())
+	case verb == 'v' && f.Flag('+'):
+		io.WriteString(f, s.format(fmt.FormatString(f, verb), -1))
+	default:
+		io.WriteString(f, s.format(fmt.FormatString(f, verb), FormatLimit))
+	}
+}
+
+// LogValue implements slog.LogValuer. The set is logged as an array of
+// at most LogLimit elements, sorted as in String.
+func (s Set[Elem]) LogValue() slog.Value {
+	r := s.sorted()
+	if len(r) > LogLimit {
+		r = r[:LogLimit]
+	}
+	return slog.AnyValue(r)
+}
+
+// format prints the sorted elements of s with the given format, printing
+// at most limit elements. A negative limit prints every element.
+func (s Set[Elem]) format(format string, limit int) string {
+	r := s.sorted()
+	var b strings.Builder
+	b.WriteString("{")
+	for i, v := range r {
+		if i == limit {
+			fmt.Fprintf(&b, ", ... (%d more)", len(r)-limit)
+			break
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, format, v)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func (s Set[Elem]) sorted() []Elem {
+	r := s.ToSlice()
+	order.Sort(r)
+	return r
+}
@@ -0,0 +1,119 @@
+package set
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	type point struct{ X, Y int }
+
+	tests := map[string]struct {
+		format string
+		arg    any
+		want   string
+	}{
+		"initialization and empty": {
+			format: "%v",
+			arg:    Set[int]{},
+			want:   "{}",
+		},
+		"integers": {
+			format: "%v",
+			arg:    Of(10, 2, -3),
+			want:   "{-3, 2, 10}",
+		},
+		"strings": {
+			format: "%s",
+			arg:    Of("b", "c", "a"),
+			want:   "{a, b, c}",
+		},
+		"quoted strings": {
+			format: "%q",
+			arg:    Of("b", "a"),
+			want:   `{"a", "b"}`,
+		},
+		"hexadecimal": {
+			format: "%x",
+			arg:    Of(255, 16),
+			want:   "{10, ff}",
+		},
+		"structs": {
+			format: "%+v",
+			arg:    Of(point{2, 1}, point{1, 2}),
+			want:   "{{X:1 Y:2}, {X:2 Y:1}}",
+		},
+		"go syntax": {
+			format: "%#v",
+			arg:    Of(2, 1),
+			want:   "set.Of[int](1, 2)",
+		},
+		"go syntax strings": {
+			format: "%#v",
+			arg:    Of("a"),
+			want:   `set.Of[string]("a")`,
+		},
+		"pointer": {
+			format: "%v",
+			arg:    func() *Set[int] { s := Of(1); return &s }(),
+			want:   "{1}",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got := fmt.Sprintf(tt.format, tt.arg)
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTruncated(t *testing.T) {
+	t.Parallel()
+
+	s := Set[int]{}
+	for i := 0; i < FormatLimit+3; i++ {
+		s.Add(i)
+	}
+	if got := s.String(); !strings.HasSuffix(got, fmt.Sprintf("%d, ... (3 more)}", FormatLimit-1)) {
+		t.Fatalf("got %v", got)
+	}
+	if got := fmt.Sprintf("%+v", s); !strings.HasSuffix(got, fmt.Sprintf("%d}", FormatLimit+2)) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	t.Parallel()
+
+	s := Set[int]{}
+	for i := LogLimit + 5; i > 0; i-- {
+		s.Add(i)
+	}
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("msg", "small", Of(3, 1, 2), "large", s)
+
+	want := `"small":[1,2,3],"large":[1,2,3,`
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Fatalf("got %v, want to contain %v", got, want)
+	}
+	if got, want := s.LogValue().Any().([]int), LogLimit; len(got) != want {
+		t.Fatalf("got %v elements, want %v", len(got), want)
+	}
+}
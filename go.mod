@@ -1,5 +1,17 @@
 module github.com/tacomeet/go-set
 
-go 1.18
+go 1.21
 
 require golang.org/x/exp v0.0.0-20220317015231-48e79f11773a
+
+require (
+	github.com/fxamacker/cbor/v2 v2.9.4
+	github.com/google/go-cmp v0.7.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+)
@@ -0,0 +1,460 @@
+package set
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"reflect"
+)
+
+// HashSet is a set backed by an open-addressing hash table instead of
+// a Go map. Its slots hold only the elements, with one control byte
+// each, and are probed a group of 8 at a time as in a Swiss table.
+//
+// HashSet has the methods of Set, so the backing store of a set can be
+// chosen where it is constructed. The batch methods AddSlice and
+// ContainsEach hash a run of elements before probing for them, which is
+// faster than adding or looking them up one at a time.
+//
+// The zero value is an empty set that hashes elements with
+// maphash.Comparable, or before Go 1.24, with a slower hash that reads
+// them by reflection.
+type HashSet[Elem comparable] struct {
+	hash func(Elem) uint64
+	// ctrl holds a control byte per slot: ctrlEmpty, ctrlDeleted, or
+	// the low 7 bits of the hash of the element in the slot.
+	ctrl  []byte
+	slots []Elem
+	n     int
+	// free is the number of empty slots that can be filled
+	// before the table has to grow.
+	free int
+}
+
+const (
+	groupSlots  = 8
+	ctrlEmpty   = 0x80
+	ctrlDeleted = 0xfe
+
+	lsbs = 0x0101010101010101
+	msbs = 0x8080808080808080
+
+	// hashBatch is the number of hashes computed ahead by the batch methods.
+	hashBatch = 64
+)
+
+// NewHashSet returns a new empty set with room for cap elements that
+// hashes elements like the zero HashSet.
+func NewHashSet[Elem comparable](cap int) *HashSet[Elem] {
+	return NewHashSetFunc[Elem](cap, nil)
+}
+
+// NewHashSetFunc is like NewHashSet, but hashes elements with hash.
+// Equal elements must have equal hashes, and all 64 bits of the hash
+// should be well distributed. If hash is nil, the default hash is used.
+func NewHashSetFunc[Elem comparable](cap int, hash func(Elem) uint64) *HashSet[Elem] {
+	s := &HashSet[Elem]{hash: hash}
+	s.resize(cap)
+	s.init()
+	return s
+}
+
+// HashSetOf returns a new hash set containing the listed elements.
+func HashSetOf[Elem comparable](v ...Elem) *HashSet[Elem] {
+	s := NewHashSet[Elem](len(v))
+	s.AddSlice(v)
+	return s
+}
+
+// init sets up the hash function and table of a zero set.
+func (s *HashSet[Elem]) init() {
+	if s.hash == nil {
+		s.hash = defaultHash[Elem]()
+	}
+	if s.ctrl == nil {
+		s.resize(0)
+	}
+}
+
+// Add adds elements to a set.
+func (s *HashSet[Elem]) Add(v ...Elem) {
+	s.AddSlice(v)
+}
+
+// AddSlice adds the elements of v to the set.
+func (s *HashSet[Elem]) AddSlice(v []Elem) {
+	s.init()
+	if s.free < len(v) {
+		s.resize(s.n + len(v))
+	}
+	var hashes [hashBatch]uint64
+	for len(v) > 0 {
+		batch := v[:min(len(v), hashBatch)]
+		for i, v := range batch {
+			if v != v {
+				panic("element in set has to be equal to itself")
+			}
+			hashes[i] = s.hash(v)
+		}
+		for i, v := range batch {
+			s.insert(v, hashes[i])
+		}
+		v = v[len(batch):]
+	}
+}
+
+// AddSet adds the elements of set s2 to s.
+func (s *HashSet[Elem]) AddSet(s2 Interface[Elem]) {
+	s.init()
+	if s.free < s2.Len() {
+		s.resize(s.n + s2.Len())
+	}
+	s2.Do(func(v Elem) bool {
+		s.insert(v, s.hash(v))
+		return true
+	})
+}
+
+// Remove removes elements from a set.
+// Elements that are not present are ignored.
+func (s *HashSet[Elem]) Remove(v ...Elem) {
+	for _, v := range v {
+		s.remove(v)
+	}
+}
+
+// RemoveSet removes the elements of set s2 from s.
+// Elements present in s2 but not s are ignored.
+func (s *HashSet[Elem]) RemoveSet(s2 Interface[Elem]) {
+	s2.Do(func(v Elem) bool {
+		s.remove(v)
+		return true
+	})
+}
+
+// remove removes v from the set if it is present.
+func (s *HashSet[Elem]) remove(v Elem) {
+	if s.n == 0 {
+		return
+	}
+	if i := s.find(v, s.hash(v)); i >= 0 {
+		s.removeAt(i)
+	}
+}
+
+// removeAt empties slot i, which must hold an element.
+func (s *HashSet[Elem]) removeAt(i int) {
+	// A group that has an empty slot ends every probe that reaches
+	// it, so the slot can be emptied rather than marked deleted.
+	g := i &^ (groupSlots - 1)
+	if matchEmpty(binary.LittleEndian.Uint64(s.ctrl[g:])) != 0 {
+		s.ctrl[i] = ctrlEmpty
+		s.free++
+	} else {
+		s.ctrl[i] = ctrlDeleted
+	}
+	var zero Elem
+	s.slots[i] = zero
+	s.n--
+}
+
+// Contains reports whether v is in the set.
+func (s *HashSet[Elem]) Contains(v Elem) bool {
+	if s.n == 0 {
+		return false
+	}
+	return s.find(v, s.hash(v)) >= 0
+}
+
+// ContainsEach reports, for each element of v, whether it is in the set.
+func (s *HashSet[Elem]) ContainsEach(v []Elem) []bool {
+	r := make([]bool, len(v))
+	if s.n == 0 {
+		return r
+	}
+	var hashes [hashBatch]uint64
+	for off := 0; off < len(v); off += hashBatch {
+		batch := v[off:min(len(v), off+hashBatch)]
+		for i, v := range batch {
+			hashes[i] = s.hash(v)
+		}
+		for i, v := range batch {
+			r[off+i] = s.find(v, hashes[i]) >= 0
+		}
+	}
+	return r
+}
+
+// ContainsAny reports whether any of the elements in s2 are in s.
+func (s *HashSet[Elem]) ContainsAny(s2 Interface[Elem]) bool {
+	found := false
+	s2.Do(func(v Elem) bool {
+		found = s.Contains(v)
+		return !found
+	})
+	return found
+}
+
+// ContainsAll reports whether all of the elements in s2 are in s.
+func (s *HashSet[Elem]) ContainsAll(s2 Interface[Elem]) bool {
+	if s2.Len() > s.Len() {
+		return false
+	}
+	all := true
+	s2.Do(func(v Elem) bool {
+		all = s.Contains(v)
+		return all
+	})
+	return all
+}
+
+// Equal reports whether s and s2 contain the same elements.
+func (s *HashSet[Elem]) Equal(s2 Set[Elem]) bool {
+	return s.n == s2.Len() && s.ContainsAll(s2)
+}
+
+// Len returns the number of elements in s.
+func (s *HashSet[Elem]) Len() int {
+	return s.n
+}
+
+// Do calls f on every element in the set s,
+// stopping if f returns false.
+// f should not change s.
+// f will be called on values in an indeterminate order.
+func (s *HashSet[Elem]) Do(f func(Elem) bool) {
+	for i, c := range s.ctrl {
+		if c&ctrlEmpty == 0 && !f(s.slots[i]) {
+			break
+		}
+	}
+}
+
+// ToSlice returns the elements in the set s as a slice.
+// The values will be in an indeterminate order.
+func (s *HashSet[Elem]) ToSlice() []Elem {
+	r := make([]Elem, 0, s.n)
+	s.Do(func(v Elem) bool {
+		r = append(r, v)
+		return true
+	})
+	return r
+}
+
+// ToSet returns the elements of s as a Set.
+func (s *HashSet[Elem]) ToSet() Set[Elem] {
+	r := WithCap[Elem](s.n)
+	s.Do(func(v Elem) bool {
+		r.m[v] = struct{}{}
+		return true
+	})
+	return r
+}
+
+// Clone returns a copy of s as a Set, like ToSet.
+func (s *HashSet[Elem]) Clone() Set[Elem] {
+	return s.ToSet()
+}
+
+// Retain deletes any elements from s for which keep returns false.
+func (s *HashSet[Elem]) Retain(keep func(Elem) bool) {
+	for i, c := range s.ctrl {
+		if c&ctrlEmpty == 0 && !keep(s.slots[i]) {
+			s.removeAt(i)
+		}
+	}
+}
+
+// Pop removes and returns an arbitrary element from s.
+func (s *HashSet[Elem]) Pop() (Elem, bool) {
+	if s.n > 0 {
+		for i, c := range s.ctrl {
+			if c&ctrlEmpty == 0 {
+				v := s.slots[i]
+				s.removeAt(i)
+				return v, true
+			}
+		}
+	}
+	var r Elem
+	return r, false
+}
+
+// Clear removes all elements from s, leaving it empty.
+// The memory used by s is kept for reuse.
+func (s *HashSet[Elem]) Clear() {
+	for i := range s.ctrl {
+		s.ctrl[i] = ctrlEmpty
+	}
+	clear(s.slots)
+	s.n = 0
+	s.free = maxLoad(len(s.slots))
+}
+
+// find returns the slot holding v, whose hash is h, or -1.
+func (s *HashSet[Elem]) find(v Elem, h uint64) int {
+	mask := len(s.ctrl)/groupSlots - 1
+	g := int(h>>7) & mask
+	for step := 1; ; step++ {
+		off := g * groupSlots
+		w := binary.LittleEndian.Uint64(s.ctrl[off:])
+		for m := matchByte(w, byte(h&0x7f)); m != 0; m &= m - 1 {
+			i := off + bits.TrailingZeros64(m)/8
+			if s.slots[i] == v {
+				return i
+			}
+		}
+		if matchEmpty(w) != 0 {
+			return -1
+		}
+		g = (g + step) & mask
+	}
+}
+
+// insert adds v, whose hash is h, if it is not already in the set.
+func (s *HashSet[Elem]) insert(v Elem, h uint64) {
+	if s.find(v, h) >= 0 {
+		return
+	}
+	if s.free == 0 {
+		s.resize(2 * s.n)
+	}
+	s.place(v, h)
+}
+
+// place stores v, whose hash is h, in the first free slot of its probe
+// sequence. v must not be in the set.
+func (s *HashSet[Elem]) place(v Elem, h uint64) {
+	mask := len(s.ctrl)/groupSlots - 1
+	g := int(h>>7) & mask
+	for step := 1; ; step++ {
+		off := g * groupSlots
+		w := binary.LittleEndian.Uint64(s.ctrl[off:])
+		if m := w & msbs; m != 0 {
+			i := off + bits.TrailingZeros64(m)/8
+			if s.ctrl[i] == ctrlEmpty {
+				s.free--
+			}
+			s.ctrl[i] = byte(h & 0x7f)
+			s.slots[i] = v
+			s.n++
+			return
+		}
+		g = (g + step) & mask
+	}
+}
+
+// resize moves the elements to a new table with room for at least n
+// elements, dropping deleted slots.
+func (s *HashSet[Elem]) resize(n int) {
+	n = max(n, s.n)
+	groups := 1
+	for maxLoad(groups*groupSlots) < n {
+		groups *= 2
+	}
+	ctrl, slots := s.ctrl, s.slots
+	s.ctrl = make([]byte, groups*groupSlots)
+	for i := range s.ctrl {
+		s.ctrl[i] = ctrlEmpty
+	}
+	s.slots = make([]Elem, groups*groupSlots)
+	s.n = 0
+	s.free = maxLoad(len(s.slots))
+	for i, c := range ctrl {
+		if c&ctrlEmpty == 0 {
+			s.place(slots[i], s.hash(slots[i]))
+		}
+	}
+}
+
+// maxLoad returns the number of elements a table of n slots may hold.
+// Tables are kept at most 7/8 full, so every probe reaches an empty slot.
+func maxLoad(n int) int {
+	return n - n/8
+}
+
+// matchByte returns a mask with the high bit set in each byte of w
+// that equals b. It may also flag a byte following one that matches,
+// so callers must check the slots it reports.
+func matchByte(w uint64, b byte) uint64 {
+	x := w ^ (lsbs * uint64(b))
+	return (x - lsbs) &^ x & msbs
+}
+
+// matchEmpty returns a mask with the high bit set in each byte of w
+// that is ctrlEmpty.
+func matchEmpty(w uint64) uint64 {
+	return w &^ (w << 6) & msbs
+}
+
+// reflectHash returns a hash function for any comparable type that
+// reads elements by reflection. It is the default hash before Go 1.24.
+func reflectHash[Elem comparable]() func(Elem) uint64 {
+	seed := maphash.MakeSeed()
+	return func(v Elem) uint64 {
+		var h maphash.Hash
+		h.SetSeed(seed)
+		writeValue(&h, reflect.ValueOf(&v).Elem())
+		return h.Sum64()
+	}
+}
+
+// writeValue writes v to h so that values that are == write the same
+// bytes.
+func writeValue(h *maphash.Hash, v reflect.Value) {
+	var b [8]byte
+	writeUint := func(u uint64) {
+		binary.LittleEndian.PutUint64(b[:], u)
+		h.Write(b[:])
+	}
+	writeFloat := func(f float64) {
+		if f == 0 {
+			// -0 == +0.
+			f = 0
+		}
+		writeUint(math.Float64bits(f))
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeFloat(real(v.Complex()))
+		writeFloat(imag(v.Complex()))
+	case reflect.String:
+		writeUint(uint64(v.Len()))
+		h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint(uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+		} else {
+			writeValue(h, v.Elem())
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		// Blank fields are not compared.
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Name != "_" {
+				writeValue(h, v.Field(i))
+			}
+		}
+	default:
+		panic(fmt.Sprintf("set: hash of unhashable type %v", v.Type()))
+	}
+}
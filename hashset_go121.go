@@ -0,0 +1,9 @@
+//go:build !go1.24
+
+package set
+
+// defaultHash returns the hash function of a HashSet created without one.
+// maphash.Comparable needs Go 1.24, so older releases hash by reflection.
+func defaultHash[Elem comparable]() func(Elem) uint64 {
+	return reflectHash[Elem]()
+}
@@ -0,0 +1,13 @@
+//go:build go1.24
+
+package set
+
+import "hash/maphash"
+
+// defaultHash returns the hash function of a HashSet created without one.
+func defaultHash[Elem comparable]() func(Elem) uint64 {
+	seed := maphash.MakeSeed()
+	return func(v Elem) uint64 {
+		return maphash.Comparable(seed, v)
+	}
+}
@@ -0,0 +1,325 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestHashSet(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		hash func(int) uint64
+		ops  func(s *HashSet[int])
+		want Set[int]
+	}{
+		"zero value": {
+			ops:  func(s *HashSet[int]) {},
+			want: Of[int](),
+		},
+		"add": {
+			ops:  func(s *HashSet[int]) { s.Add(1, 2, 3) },
+			want: Of(1, 2, 3),
+		},
+		"add duplicates": {
+			ops:  func(s *HashSet[int]) { s.Add(1, 1, 2, 2) },
+			want: Of(1, 2),
+		},
+		"add zero value element": {
+			ops:  func(s *HashSet[int]) { s.Add(0) },
+			want: Of(0),
+		},
+		"grow": {
+			ops: func(s *HashSet[int]) {
+				for i := 0; i < 100; i++ {
+					s.Add(i)
+				}
+				s.Remove(5, 6, 7)
+				for i := 5; i < 100; i++ {
+					s.Remove(i)
+				}
+			},
+			want: Of(0, 1, 2, 3, 4),
+		},
+		"remove": {
+			ops: func(s *HashSet[int]) {
+				s.Add(1, 2, 3)
+				s.Remove(2, 4)
+			},
+			want: Of(1, 3),
+		},
+		"remove zero value element": {
+			ops: func(s *HashSet[int]) {
+				s.Add(1, 2)
+				s.Remove(0)
+			},
+			want: Of(1, 2),
+		},
+		"clear": {
+			ops: func(s *HashSet[int]) {
+				s.Add(1, 2, 3)
+				s.Clear()
+				s.Add(4)
+			},
+			want: Of(4),
+		},
+		"colliding hashes": {
+			hash: func(int) uint64 { return 42 },
+			ops: func(s *HashSet[int]) {
+				for i := 0; i < 50; i++ {
+					s.Add(i)
+				}
+				for i := 0; i < 50; i += 2 {
+					s.Remove(i)
+				}
+			},
+			want: func() Set[int] {
+				s := Set[int]{}
+				for i := 1; i < 50; i += 2 {
+					s.Add(i)
+				}
+				return s
+			}(),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &HashSet[int]{}
+			if tt.hash != nil {
+				s = NewHashSetFunc(0, tt.hash)
+			}
+			tt.ops(s)
+			got := s.ToSet()
+			if !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if s.Len() != tt.want.Len() {
+				t.Fatalf("len: got %v, want %v", s.Len(), tt.want.Len())
+			}
+			for _, v := range tt.want.ToSlice() {
+				if !s.Contains(v) {
+					t.Fatalf("contains %v: got false, want true", v)
+				}
+			}
+			if s.Contains(-1) {
+				t.Fatalf("contains -1: got true, want false")
+			}
+		})
+	}
+}
+
+func TestHashSetRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	s := NewHashSet[int](0)
+	m := Set[int]{}
+	for i := 0; i < 100000; i++ {
+		v := r.Intn(1000)
+		switch r.Intn(3) {
+		case 0, 1:
+			s.Add(v)
+			m.Add(v)
+		default:
+			s.Remove(v)
+			m.Remove(v)
+		}
+		if s.Contains(v) != m.Contains(v) {
+			t.Fatalf("step %d: contains %v: got %v, want %v", i, v, s.Contains(v), m.Contains(v))
+		}
+	}
+	if got := s.ToSet(); !got.Equal(m) {
+		t.Fatalf("got %v, want %v", got, m)
+	}
+	if len(s.slots) > 4096 {
+		t.Fatalf("table size: got %v, want at most 4096", len(s.slots))
+	}
+}
+
+func TestHashSetBatch(t *testing.T) {
+	t.Parallel()
+
+	v := make([]string, 200)
+	for i := range v {
+		v[i] = strconv.Itoa(i)
+	}
+	s := HashSetOf(v[:100]...)
+	s.AddSlice(v[50:150])
+
+	got := s.ContainsEach(v)
+	want := make([]bool, len(v))
+	for i := range want {
+		want[i] = i < 150
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if s.Len() != 150 {
+		t.Fatalf("len: got %v, want %v", s.Len(), 150)
+	}
+
+	var empty HashSet[string]
+	if got := empty.ContainsEach(v[:3]); !slices.Equal(got, []bool{false, false, false}) {
+		t.Fatalf("got %v, want %v", got, []bool{false, false, false})
+	}
+}
+
+func TestHashSetAddWithNaN(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "element in set has to be equal to itself" {
+			t.Fatalf("got %v, want %v", r, "element in set has to be equal to itself")
+		}
+	}()
+	var s HashSet[float64]
+	s.Add(1, math.NaN())
+}
+
+func TestReflectHash(t *testing.T) {
+	t.Parallel()
+
+	type key struct {
+		a int
+		_ string
+		s string
+		f float64
+		i any
+		p *int
+	}
+	x := 1
+	h := reflectHash[key]()
+	equal := [][2]key{
+		{{a: 1, s: "a", p: &x}, {a: 1, s: "a", p: &x}},
+		{{f: 0}, {f: math.Copysign(0, -1)}},
+		{{i: 3}, {i: 3}},
+		{{i: [2]string{"a", "b"}}, {i: [2]string{"a", "b"}}},
+	}
+	for _, pair := range equal {
+		if pair[0] != pair[1] {
+			t.Fatalf("test case %v: values are not equal", pair)
+		}
+		if h(pair[0]) != h(pair[1]) {
+			t.Fatalf("got different hashes for %v and %v", pair[0], pair[1])
+		}
+	}
+	if h(key{s: "a"}) == h(key{s: "b"}) {
+		t.Fatalf("got equal hashes for different strings")
+	}
+
+	s := NewHashSetFunc(0, h)
+	for i := 0; i < 100; i++ {
+		s.Add(key{a: i, i: strconv.Itoa(i)})
+	}
+	for i := 0; i < 100; i++ {
+		if !s.Contains(key{a: i, i: strconv.Itoa(i)}) {
+			t.Fatalf("missing %v", i)
+		}
+	}
+	if s.Contains(key{a: 1}) {
+		t.Fatalf("got true, want false")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("got no panic for an unhashable value")
+		}
+	}()
+	reflectHash[any]()([]int{1})
+}
+
+func TestMatchByte(t *testing.T) {
+	t.Parallel()
+
+	w := uint64(0x80_05_fe_05_80_80_05_00)
+	if got, want := matchByte(w, 0x05), uint64(0x00_80_00_80_00_00_80_00); got != want {
+		t.Fatalf("got %#x, want %#x", got, want)
+	}
+	if got, want := matchEmpty(w), uint64(0x80_00_00_00_80_80_00_00); got != want {
+		t.Fatalf("got %#x, want %#x", got, want)
+	}
+}
+
+// intHash is a fast hash for int keys, to compare the table itself
+// without the cost of maphash.
+func intHash(v int) uint64 {
+	h := uint64(v) * 0x9e3779b97f4a7c15
+	return h ^ h>>32
+}
+
+func hashSetBenchmarks[Elem comparable](b *testing.B, name string, keys []Elem, hash func(Elem) uint64) {
+	n := len(keys) / 2
+	present, absent := keys[:n], keys[n:]
+	m := Set[Elem]{}
+	m.Add(present...)
+	h := NewHashSetFunc(0, hash)
+	h.AddSlice(present)
+
+	b.Run(name+"/Contains/map", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m.Contains(keys[i%len(keys)])
+		}
+	})
+	b.Run(name+"/Contains/hash", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h.Contains(keys[i%len(keys)])
+		}
+	})
+	b.Run(name+"/ContainsEach/hash", func(b *testing.B) {
+		for i := 0; i < b.N; i += len(keys) {
+			h.ContainsEach(keys)
+		}
+	})
+	b.Run(name+"/AddRemove/map", func(b *testing.B) {
+		s := m.Clone()
+		for i := 0; i < b.N; i++ {
+			v := absent[i%len(absent)]
+			s.Add(v)
+			s.Remove(v)
+		}
+	})
+	b.Run(name+"/AddRemove/hash", func(b *testing.B) {
+		s := NewHashSetFunc(0, hash)
+		s.AddSlice(present)
+		for i := 0; i < b.N; i++ {
+			v := absent[i%len(absent)]
+			s.Add(v)
+			s.Remove(v)
+		}
+	})
+	b.Run(name+"/Build/map", func(b *testing.B) {
+		for i := 0; i < b.N; i += len(keys) {
+			s := Set[Elem]{}
+			s.Add(keys...)
+		}
+	})
+	b.Run(name+"/Build/hash", func(b *testing.B) {
+		for i := 0; i < b.N; i += len(keys) {
+			s := NewHashSetFunc(0, hash)
+			s.AddSlice(keys)
+		}
+	})
+}
+
+func BenchmarkHashSet(b *testing.B) {
+	for _, n := range []int{1 << 10, 1 << 18} {
+		r := rand.New(rand.NewSource(1))
+		ints := make([]int, n)
+		strs := make([]string, n)
+		for i := range ints {
+			ints[i] = r.Int()
+			strs[i] = strconv.Itoa(ints[i])
+		}
+		hashSetBenchmarks(b, fmt.Sprintf("int/n=%d", n), ints, nil)
+		hashSetBenchmarks(b, fmt.Sprintf("int/intHash/n=%d", n), ints, intHash)
+		hashSetBenchmarks(b, fmt.Sprintf("string/n=%d", n), strs, nil)
+	}
+}
@@ -0,0 +1,189 @@
+package set
+
+import "sort"
+
+// InvertedIndex indexes documents by the terms they contain, and answers
+// boolean queries over them.
+//
+// Queries are expressions whose leaves are posting lists, the sets of
+// documents containing a term; see Term, And, Or and Not. They are
+// evaluated like any other Expr, so intersections are computed from the
+// shortest posting list up, and they read the index when they are
+// evaluated rather than when they are built.
+//
+// The index counts how often each term was added to each document, so
+// results can be ranked by term frequency; see Rank and TFIDF.
+// The zero value is an empty index.
+type InvertedIndex[Term, Doc comparable] struct {
+	// rel relates each document to its terms; its inverse holds
+	// the posting lists.
+	rel Relation[Doc, Term]
+	tf  map[Pair[Doc, Term]]int
+}
+
+// Add indexes doc under the listed terms. A term listed more than once,
+// or added to the same document again, counts more than once toward its
+// frequency in doc. A document is in the index while it has at least
+// one term.
+func (x *InvertedIndex[Term, Doc]) Add(doc Doc, terms ...Term) {
+	if x.tf == nil {
+		x.tf = map[Pair[Doc, Term]]int{}
+	}
+	for _, t := range terms {
+		x.rel.Add(doc, t)
+		x.tf[Pair[Doc, Term]{doc, t}]++
+	}
+}
+
+// Remove removes doc and all of its terms from the index.
+func (x *InvertedIndex[Term, Doc]) Remove(doc Doc) {
+	terms := x.rel.fwd[doc]
+	for t := range terms.m {
+		x.rel.Remove(doc, t)
+		delete(x.tf, Pair[Doc, Term]{doc, t})
+	}
+}
+
+// Len returns the number of documents in the index.
+func (x *InvertedIndex[Term, Doc]) Len() int {
+	return len(x.rel.fwd)
+}
+
+// Terms returns the set of terms of doc.
+func (x *InvertedIndex[Term, Doc]) Terms(doc Doc) Set[Term] {
+	s := x.rel.fwd[doc]
+	return s.Clone()
+}
+
+// TF returns the number of times t was added to doc.
+func (x *InvertedIndex[Term, Doc]) TF(doc Doc, t Term) int {
+	return x.tf[Pair[Doc, Term]{doc, t}]
+}
+
+// DF returns the number of documents containing t.
+func (x *InvertedIndex[Term, Doc]) DF(t Term) int {
+	s := x.rel.inv[t]
+	return s.Len()
+}
+
+// Term returns the query matching the documents that contain t.
+func (x *InvertedIndex[Term, Doc]) Term(t Term) *Expr[Doc] {
+	return Leaf[Doc](posting[Term, Doc]{x, t})
+}
+
+// All returns the query matching every document.
+func (x *InvertedIndex[Term, Doc]) All() *Expr[Doc] {
+	return Leaf[Doc](allDocs[Term, Doc]{x})
+}
+
+// And returns the query matching the documents that contain every term.
+// With no terms, it matches every document.
+func (x *InvertedIndex[Term, Doc]) And(terms ...Term) *Expr[Doc] {
+	if len(terms) == 0 {
+		return x.All()
+	}
+	return x.Term(terms[0]).Intersect(x.terms(terms[1:])...)
+}
+
+// Or returns the query matching the documents that contain any term.
+func (x *InvertedIndex[Term, Doc]) Or(terms ...Term) *Expr[Doc] {
+	if len(terms) == 0 {
+		return Leaf[Doc](&Set[Doc]{})
+	}
+	return x.Term(terms[0]).Union(x.terms(terms[1:])...)
+}
+
+// Not returns the query matching the documents that q does not match.
+func (x *InvertedIndex[Term, Doc]) Not(q *Expr[Doc]) *Expr[Doc] {
+	return x.All().Minus(q)
+}
+
+func (x *InvertedIndex[Term, Doc]) terms(terms []Term) []*Expr[Doc] {
+	r := make([]*Expr[Doc], len(terms))
+	for i, t := range terms {
+		r[i] = x.Term(t)
+	}
+	return r
+}
+
+// Rank returns the documents in docs ordered by decreasing score.
+// The order of documents with equal scores is indeterminate.
+func Rank[Doc comparable](docs Interface[Doc], score func(Doc) float64) []Doc {
+	type scored struct {
+		doc   Doc
+		score float64
+	}
+	r := make([]scored, 0, docs.Len())
+	docs.Do(func(d Doc) bool {
+		r = append(r, scored{d, score(d)})
+		return true
+	})
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].score > r[j].score
+	})
+	ds := make([]Doc, len(r))
+	for i := range r {
+		ds[i] = r[i].doc
+	}
+	return ds
+}
+
+// TFIDF returns a score function for Rank that sums, over terms, the
+// frequency of each term in a document weighted by the inverse of the
+// number of documents containing it.
+func (x *InvertedIndex[Term, Doc]) TFIDF(terms ...Term) func(Doc) float64 {
+	idf := make([]float64, len(terms))
+	for i, t := range terms {
+		if df := x.DF(t); df > 0 {
+			idf[i] = float64(x.Len()) / float64(df)
+		}
+	}
+	return func(d Doc) float64 {
+		score := 0.0
+		for i, t := range terms {
+			score += float64(x.TF(d, t)) * idf[i]
+		}
+		return score
+	}
+}
+
+// posting is the posting list of a term, read from the index on each use.
+type posting[Term, Doc comparable] struct {
+	x *InvertedIndex[Term, Doc]
+	t Term
+}
+
+func (p posting[Term, Doc]) Contains(d Doc) bool {
+	return p.x.rel.Contains(d, p.t)
+}
+
+func (p posting[Term, Doc]) Len() int {
+	return p.x.DF(p.t)
+}
+
+func (p posting[Term, Doc]) Do(f func(Doc) bool) {
+	s := p.x.rel.inv[p.t]
+	s.Do(f)
+}
+
+// allDocs is the set of documents in an index.
+type allDocs[Term, Doc comparable] struct {
+	x *InvertedIndex[Term, Doc]
+}
+
+func (a allDocs[Term, Doc]) Contains(d Doc) bool {
+	_, ok := a.x.rel.fwd[d]
+	return ok
+}
+
+func (a allDocs[Term, Doc]) Len() int {
+	return a.x.Len()
+}
+
+func (a allDocs[Term, Doc]) Do(f func(Doc) bool) {
+	for d := range a.x.rel.fwd {
+		if !f(d) {
+			break
+		}
+	}
+}
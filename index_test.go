@@ -0,0 +1,150 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func newTestIndex() *InvertedIndex[string, int] {
+	var x InvertedIndex[string, int]
+	x.Add(1, "go", "set", "generics")
+	x.Add(2, "go", "map")
+	x.Add(3, "rust", "set", "set")
+	x.Add(4, "go", "go", "go", "set")
+	return &x
+}
+
+func TestInvertedIndexQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		query func(x *InvertedIndex[string, int]) *Expr[int]
+		want  Set[int]
+	}{
+		"term": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] { return x.Term("go") },
+			want:  Of(1, 2, 4),
+		},
+		"unknown term": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] { return x.Term("java") },
+			want:  Of[int](),
+		},
+		"and": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] { return x.And("go", "set") },
+			want:  Of(1, 4),
+		},
+		"and nothing": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] { return x.And() },
+			want:  Of(1, 2, 3, 4),
+		},
+		"or": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] { return x.Or("map", "rust", "java") },
+			want:  Of(2, 3),
+		},
+		"or nothing": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] { return x.Or() },
+			want:  Of[int](),
+		},
+		"not": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] { return x.Not(x.Term("go")) },
+			want:  Of(3),
+		},
+		"combined": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] {
+				return x.And("set").Minus(x.Or("rust", "generics"))
+			},
+			want: Of(4),
+		},
+		"after removal": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] {
+				q := x.And("go", "set")
+				x.Remove(4)
+				x.Remove(10)
+				return q
+			},
+			want: Of(1),
+		},
+		"after addition": {
+			query: func(x *InvertedIndex[string, int]) *Expr[int] {
+				q := x.Not(x.Term("java"))
+				x.Add(5, "java")
+				x.Add(6)
+				return q
+			},
+			want: Of(1, 2, 3, 4),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			x := newTestIndex()
+			q := tt.query(x)
+			if got := q.Eval(); !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for _, d := range []int{1, 2, 3, 4, 5, 6} {
+				if got, want := q.Contains(d), tt.want.Contains(d); got != want {
+					t.Fatalf("contains %v: got %v, want %v", d, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestInvertedIndexShortestPostingFirst(t *testing.T) {
+	t.Parallel()
+
+	x := newTestIndex()
+	args := x.And("go", "set", "generics").bySize()
+	var got []string
+	for _, a := range args {
+		got = append(got, a.leaf.(posting[string, int]).t)
+	}
+	if want := []string{"generics", "go", "set"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestInvertedIndexStats(t *testing.T) {
+	t.Parallel()
+
+	x := newTestIndex()
+	if x.Len() != 4 {
+		t.Fatalf("len: got %v, want %v", x.Len(), 4)
+	}
+	if got := x.TF(4, "go"); got != 3 {
+		t.Fatalf("tf: got %v, want %v", got, 3)
+	}
+	if got := x.DF("set"); got != 3 {
+		t.Fatalf("df: got %v, want %v", got, 3)
+	}
+	if got := x.Terms(3); !got.Equal(Of("rust", "set")) {
+		t.Fatalf("terms: got %v, want %v", got, Of("rust", "set"))
+	}
+
+	x.Remove(4)
+	if x.Len() != 3 || x.TF(4, "go") != 0 || x.DF("set") != 2 {
+		t.Fatalf("got len %v, tf %v, df %v after removal, want 3, 0, 2", x.Len(), x.TF(4, "go"), x.DF("set"))
+	}
+}
+
+func TestRank(t *testing.T) {
+	t.Parallel()
+
+	x := newTestIndex()
+	docs := x.Or("go", "set").Eval()
+	got := Rank[int](&docs, x.TFIDF("go", "set"))
+	// Both terms are in 3 of the 4 documents, so the scores are
+	// proportional to the total frequencies: 4, then 1 and 3, then 2.
+	if len(got) != 4 || got[0] != 4 || got[3] != 2 {
+		t.Fatalf("got %v, want 4 first and 2 last", got)
+	}
+
+	got = Rank[int](&docs, func(d int) float64 { return float64(x.TF(d, "set")) })
+	if got[0] != 3 {
+		t.Fatalf("got %v, want 3 first", got)
+	}
+}
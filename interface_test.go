@@ -0,0 +1,72 @@
+package set
+
+import "testing"
+
+var (
+	_ Interface[int] = Set[int]{}
+	_ Interface[int] = (*Set[int])(nil)
+	_ Interface[int] = (*BoundedSet[int])(nil)
+	_ Interface[int] = (*ObservableSet[int])(nil)
+	_ Interface[int] = (*SmallSet[int])(nil)
+	_ Interface[int] = (*HashSet[int])(nil)
+	_ Interface[int] = (*FileSet[int])(nil)
+)
+
+func TestInterfaceOperations(t *testing.T) {
+	t.Parallel()
+
+	newBounded := func(v ...int) Interface[int] {
+		s := NewBounded(10, BoundedOptions[int]{})
+		s.Add(v...)
+		return s
+	}
+	newObservable := func(v ...int) Interface[int] {
+		return NewObservable(Of(v...))
+	}
+
+	tests := map[string]struct {
+		arg1 Interface[int]
+		arg2 Interface[int]
+	}{
+		"set and bounded set": {
+			arg1: Of(1, 2, 3),
+			arg2: newBounded(2, 3, 4),
+		},
+		"bounded set and observable set": {
+			arg1: newBounded(1, 2, 3),
+			arg2: newObservable(2, 3, 4),
+		},
+		"observable set and set": {
+			arg1: newObservable(1, 2, 3),
+			arg2: Of(2, 3, 4),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if got, want := Union(tt.arg1, tt.arg2), Of(1, 2, 3, 4); !want.Equal(got) {
+				t.Fatalf("union: got %v, want %v", got.ToSlice(), want.ToSlice())
+			}
+			if got, want := Intersect(tt.arg1, tt.arg2), Of(2, 3); !want.Equal(got) {
+				t.Fatalf("intersect: got %v, want %v", got.ToSlice(), want.ToSlice())
+			}
+			if got, want := Difference(tt.arg1, tt.arg2), Of(1); !want.Equal(got) {
+				t.Fatalf("difference: got %v, want %v", got.ToSlice(), want.ToSlice())
+			}
+
+			s := Of(1)
+			s.AddSet(tt.arg2)
+			if want := Of(1, 2, 3, 4); !want.Equal(s) {
+				t.Fatalf("add set: got %v, want %v", s.ToSlice(), want.ToSlice())
+			}
+			if !s.ContainsAll(tt.arg1) || !s.ContainsAny(tt.arg2) {
+				t.Fatalf("contains: got false, want true")
+			}
+			s.RemoveSet(tt.arg1)
+			if want := Of(4); !want.Equal(s) {
+				t.Fatalf("remove set: got %v, want %v", s.ToSlice(), want.ToSlice())
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Package order sorts set elements for printing and for messages,
+// whatever their type.
+package order
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Sort sorts v with numbers, strings and booleans in their natural
+// order, and other values by kind and then by their printed form.
+func Sort[Elem any](v []Elem) {
+	sort.Slice(v, func(i, j int) bool {
+		return less(reflect.ValueOf(v[i]), reflect.ValueOf(v[j]))
+	})
+}
+
+// less reports whether a sorts before b in Sort.
+func less(a, b reflect.Value) bool {
+	if a.Kind() != b.Kind() {
+		return a.Kind() < b.Kind()
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
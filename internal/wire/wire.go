@@ -0,0 +1,45 @@
+// Package wire holds what the encodings of sets share: the errors they
+// report, their decoding options, and helpers for integer elements.
+//
+// Package set re-exports the errors, so that they can be defined here
+// without an import cycle.
+package wire
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// The errors exported by package set as ErrInvalidEncoding and
+// ErrDuplicate.
+var (
+	ErrInvalidEncoding = errors.New("set: invalid element encoding")
+	ErrDuplicate       = errors.New("set: duplicate element")
+)
+
+// Options configures decoding.
+type Options struct {
+	// Strict makes decoding fail with set.ErrDuplicate if an element
+	// appears more than once.
+	Strict bool
+}
+
+// Check reports whether v can be added to the set s being decoded.
+// Elements that aren't equal to themselves, such as NaN, are reported
+// as ErrInvalidEncoding rather than left to panic in Add.
+func Check[Elem comparable](s interface{ Contains(Elem) bool }, v Elem, opts Options) error {
+	if v != v {
+		return ErrInvalidEncoding
+	}
+	if opts.Strict && s.Contains(v) {
+		return fmt.Errorf("%w: %v", ErrDuplicate, v)
+	}
+	return nil
+}
+
+// IsSigned reports whether Elem is a signed integer type.
+func IsSigned[Elem constraints.Integer]() bool {
+	return ^Elem(0) < 0
+}
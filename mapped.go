@@ -0,0 +1,326 @@
+package set
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"slices"
+)
+
+// A mapped set file holds a sorted set of uint64 values:
+//
+//	header   magic "GOSETMS1", version uint32, block length uint32
+//	blocks   the values in increasing order, as little-endian uint64s,
+//	         in blocks of block length values (the last may be shorter)
+//	index    for each block, its first value as a uint64 and the CRC-32C
+//	         of its bytes as a uint32, padded to 16 bytes
+//	trailer  the number of values as a uint64, the CRC-32C of the index
+//	         and the CRC-32C of the header and the trailer up to here,
+//	         as uint32s, and the magic "GOSETEND"
+//
+// All integers are little-endian.
+const (
+	mappedMagic        = "GOSETMS1"
+	mappedEndMagic     = "GOSETEND"
+	mappedVersion      = 1
+	mappedHeaderSize   = 16
+	mappedIndexEntry   = 16
+	mappedTrailerSize  = 24
+	defaultMappedBlock = 512
+)
+
+// ErrVersion is returned when opening a mapped set file written in a
+// format version this package doesn't support.
+var ErrVersion = errors.New("set: unsupported file format version")
+
+// MappedWriter writes a mapped set file from values added in increasing
+// order. It holds only the index in memory, so it can write sets larger
+// than memory.
+type MappedWriter struct {
+	w        *bufio.Writer
+	blockLen int
+	count    uint64
+	last     uint64
+	// inBlock is the number of values in the current block,
+	// and crc their checksum so far.
+	inBlock int
+	crc     uint32
+	index   []byte
+	buf     [8]byte
+	err     error
+}
+
+// NewMappedWriter returns a writer of a mapped set file to w.
+func NewMappedWriter(w io.Writer) *MappedWriter {
+	mw := &MappedWriter{w: bufio.NewWriter(w), blockLen: defaultMappedBlock}
+	var h [mappedHeaderSize]byte
+	copy(h[:], mappedMagic)
+	binary.LittleEndian.PutUint32(h[8:], mappedVersion)
+	binary.LittleEndian.PutUint32(h[12:], uint32(mw.blockLen))
+	_, mw.err = mw.w.Write(h[:])
+	return mw
+}
+
+// Add appends v to the set. Values must be added in increasing order;
+// adding the last value again has no effect.
+func (mw *MappedWriter) Add(v uint64) error {
+	if mw.err != nil {
+		return mw.err
+	}
+	if mw.count > 0 && v <= mw.last {
+		if v == mw.last {
+			return nil
+		}
+		mw.err = fmt.Errorf("set: value %d added after %d", v, mw.last)
+		return mw.err
+	}
+	if mw.inBlock == mw.blockLen {
+		mw.endBlock()
+	}
+	if mw.inBlock == 0 {
+		mw.index = binary.LittleEndian.AppendUint64(mw.index, v)
+	}
+	binary.LittleEndian.PutUint64(mw.buf[:], v)
+	mw.crc = crc32.Update(mw.crc, crcTable, mw.buf[:])
+	if _, err := mw.w.Write(mw.buf[:]); err != nil {
+		mw.err = err
+		return err
+	}
+	mw.inBlock++
+	mw.count++
+	mw.last = v
+	return nil
+}
+
+func (mw *MappedWriter) endBlock() {
+	mw.index = binary.LittleEndian.AppendUint32(mw.index, mw.crc)
+	mw.index = binary.LittleEndian.AppendUint32(mw.index, 0)
+	mw.inBlock = 0
+	mw.crc = 0
+}
+
+// Close writes the index and trailer and flushes the file.
+// It doesn't close the underlying writer.
+func (mw *MappedWriter) Close() error {
+	if mw.err != nil {
+		return mw.err
+	}
+	if mw.inBlock > 0 {
+		mw.endBlock()
+	}
+	mw.w.Write(mw.index)
+	var t [mappedTrailerSize]byte
+	binary.LittleEndian.PutUint64(t[0:], mw.count)
+	binary.LittleEndian.PutUint32(t[8:], crc32.Checksum(mw.index, crcTable))
+	binary.LittleEndian.PutUint32(t[12:], trailerChecksum(mw.blockLen, t[:12]))
+	copy(t[16:], mappedEndMagic)
+	mw.w.Write(t[:])
+	mw.err = mw.w.Flush()
+	if mw.err == nil {
+		mw.err = errors.New("set: MappedWriter is closed")
+		return nil
+	}
+	return mw.err
+}
+
+// trailerChecksum returns the checksum of the header and the start of
+// the trailer.
+func trailerChecksum(blockLen int, trailer []byte) uint32 {
+	var h [mappedHeaderSize]byte
+	copy(h[:], mappedMagic)
+	binary.LittleEndian.PutUint32(h[8:], mappedVersion)
+	binary.LittleEndian.PutUint32(h[12:], uint32(blockLen))
+	return crc32.Update(crc32.Checksum(h[:], crcTable), crcTable, trailer)
+}
+
+// WriteMapped writes the elements of s to w as a mapped set file.
+func WriteMapped(w io.Writer, s Interface[uint64]) error {
+	v := make([]uint64, 0, s.Len())
+	s.Do(func(x uint64) bool {
+		v = append(v, x)
+		return true
+	})
+	slices.Sort(v)
+	mw := NewMappedWriter(w)
+	for _, x := range v {
+		mw.Add(x)
+	}
+	return mw.Close()
+}
+
+// MappedSet is a read-only set of uint64 values backed by a memory-mapped
+// file written by MappedWriter. Lookups read the file in place: opening
+// it takes time proportional to the size of its index, not its values,
+// and the values are never copied to the heap.
+type MappedSet struct {
+	data     []byte
+	count    int
+	blockLen int
+	// values and index are the parts of data holding them.
+	values []byte
+	index  []byte
+}
+
+// OpenMapped memory-maps the mapped set file at path. It verifies the
+// header, trailer and index; call Verify to also check the values.
+func OpenMapped(path string) (*MappedSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() < mappedHeaderSize+mappedTrailerSize {
+		return nil, fmt.Errorf("%w: %s: too short", ErrCorrupt, path)
+	}
+	data, err := mmapFile(f, int(fi.Size()))
+	if err != nil {
+		return nil, err
+	}
+	s, err := newMappedSet(data)
+	if err != nil {
+		munmap(data)
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+func newMappedSet(data []byte) (*MappedSet, error) {
+	if string(data[:8]) != mappedMagic {
+		return nil, fmt.Errorf("%w: not a mapped set file", ErrCorrupt)
+	}
+	if v := binary.LittleEndian.Uint32(data[8:]); v != mappedVersion {
+		return nil, fmt.Errorf("%w %d", ErrVersion, v)
+	}
+	blockLen := int(binary.LittleEndian.Uint32(data[12:]))
+	t := data[len(data)-mappedTrailerSize:]
+	if string(t[16:]) != mappedEndMagic || blockLen == 0 ||
+		binary.LittleEndian.Uint32(t[12:]) != trailerChecksum(blockLen, t[:12]) {
+		return nil, fmt.Errorf("%w: bad trailer", ErrCorrupt)
+	}
+	count := binary.LittleEndian.Uint64(t[0:])
+	size := uint64(len(data) - mappedHeaderSize - mappedTrailerSize)
+	if count > size/8 {
+		return nil, fmt.Errorf("%w: wrong size", ErrCorrupt)
+	}
+	blocks := (count + uint64(blockLen) - 1) / uint64(blockLen)
+	if count*8+blocks*mappedIndexEntry != size {
+		return nil, fmt.Errorf("%w: wrong size", ErrCorrupt)
+	}
+	s := &MappedSet{
+		data:     data,
+		count:    int(count),
+		blockLen: blockLen,
+		values:   data[mappedHeaderSize : mappedHeaderSize+count*8],
+	}
+	s.index = data[mappedHeaderSize+count*8 : len(data)-mappedTrailerSize]
+	if crc32.Checksum(s.index, crcTable) != binary.LittleEndian.Uint32(t[8:]) {
+		return nil, fmt.Errorf("%w: bad index checksum", ErrCorrupt)
+	}
+	return s, nil
+}
+
+// Close unmaps the file. The set must not be used afterwards.
+func (s *MappedSet) Close() error {
+	data := s.data
+	*s = MappedSet{}
+	return munmap(data)
+}
+
+// Verify checks the values of s against their checksums and order.
+func (s *MappedSet) Verify() error {
+	var prev uint64
+	for b := 0; b*s.blockLen < s.count; b++ {
+		lo, hi := b*s.blockLen, min((b+1)*s.blockLen, s.count)
+		block := s.values[lo*8 : hi*8]
+		if crc32.Checksum(block, crcTable) != binary.LittleEndian.Uint32(s.index[b*mappedIndexEntry+8:]) {
+			return fmt.Errorf("%w: bad checksum in block %d", ErrCorrupt, b)
+		}
+		if s.blockFirst(b) != s.at(lo) {
+			return fmt.Errorf("%w: wrong index entry for block %d", ErrCorrupt, b)
+		}
+		for i := lo; i < hi; i++ {
+			v := s.at(i)
+			if i > 0 && v <= prev {
+				return fmt.Errorf("%w: values out of order at %d", ErrCorrupt, i)
+			}
+			prev = v
+		}
+	}
+	return nil
+}
+
+func (s *MappedSet) at(i int) uint64 {
+	return binary.LittleEndian.Uint64(s.values[i*8:])
+}
+
+func (s *MappedSet) blockFirst(b int) uint64 {
+	return binary.LittleEndian.Uint64(s.index[b*mappedIndexEntry:])
+}
+
+// search returns the position of the first value that is at least v.
+func (s *MappedSet) search(v uint64) int {
+	// Find the first block starting after v; v can only be in the block
+	// before it.
+	lo, hi := 0, len(s.index)/mappedIndexEntry
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if s.blockFirst(m) <= v {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	if lo == 0 {
+		return 0
+	}
+	lo, hi = (lo-1)*s.blockLen, min(lo*s.blockLen, s.count)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if s.at(m) < v {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	return lo
+}
+
+// Contains reports whether v is in the set.
+func (s *MappedSet) Contains(v uint64) bool {
+	i := s.search(v)
+	return i < s.count && s.at(i) == v
+}
+
+// Len returns the number of elements in s.
+func (s *MappedSet) Len() int {
+	return s.count
+}
+
+// Do calls f on every element in the set s in increasing order,
+// stopping if f returns false.
+func (s *MappedSet) Do(f func(uint64) bool) {
+	for i := 0; i < s.count; i++ {
+		if !f(s.at(i)) {
+			break
+		}
+	}
+}
+
+// Range calls f in increasing order on every element v of s with
+// lo <= v < hi, stopping if f returns false.
+func (s *MappedSet) Range(lo, hi uint64, f func(uint64) bool) {
+	for i := s.search(lo); i < s.count; i++ {
+		v := s.at(i)
+		if v >= hi || !f(v) {
+			break
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeTestMapped(t testing.TB, s Interface[uint64]) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := WriteMapped(&b, s); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "set")
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMappedSet(t *testing.T) {
+	t.Parallel()
+
+	large := Set[uint64]{}
+	r := rand.New(rand.NewSource(1))
+	for large.Len() < 3*defaultMappedBlock+10 {
+		large.Add(r.Uint64() >> r.Intn(64))
+	}
+	tests := map[string]Set[uint64]{
+		"empty":      {},
+		"one":        Of[uint64](42),
+		"extremes":   Of[uint64](0, math.MaxUint64),
+		"one block":  Of[uint64](5, 1, 3, 9, 7),
+		"many block": large,
+	}
+
+	for name, want := range tests {
+		want := want
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := OpenMapped(writeTestMapped(t, &want))
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			defer s.Close()
+			if err := s.Verify(); err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			if s.Len() != want.Len() {
+				t.Fatalf("len: got %v, want %v", s.Len(), want.Len())
+			}
+			sorted := want.ToSlice()
+			slices.Sort(sorted)
+			var got []uint64
+			s.Do(func(v uint64) bool {
+				got = append(got, v)
+				return true
+			})
+			if !slices.Equal(got, sorted) {
+				t.Fatalf("got %v, want %v", got, sorted)
+			}
+			for _, v := range sorted {
+				if !s.Contains(v) {
+					t.Fatalf("contains %v: got false, want true", v)
+				}
+				if v > 0 && !want.Contains(v-1) && s.Contains(v-1) {
+					t.Fatalf("contains %v: got true, want false", v-1)
+				}
+			}
+		})
+	}
+}
+
+func TestMappedSetRange(t *testing.T) {
+	t.Parallel()
+
+	src := Set[uint64]{}
+	for i := uint64(0); i < 2000; i += 2 {
+		src.Add(i)
+	}
+	s, err := OpenMapped(writeTestMapped(t, &src))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	tests := map[string]struct {
+		lo, hi uint64
+		limit  int
+		want   []uint64
+	}{
+		"inside":       {lo: 10, hi: 16, want: []uint64{10, 12, 14}},
+		"odd bounds":   {lo: 1021, hi: 1027, want: []uint64{1022, 1024, 1026}},
+		"empty range":  {lo: 5, hi: 6, want: nil},
+		"before start": {lo: 0, hi: 3, want: []uint64{0, 2}},
+		"past end":     {lo: 1996, hi: math.MaxUint64, want: []uint64{1996, 1998}},
+		"stop early":   {lo: 0, hi: 100, limit: 2, want: []uint64{0, 2}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []uint64
+			s.Range(tt.lo, tt.hi, func(v uint64) bool {
+				got = append(got, v)
+				return tt.limit == 0 || len(got) < tt.limit
+			})
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMappedWriterOrder(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	w := NewMappedWriter(&b)
+	if err := w.Add(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Add(2); err != nil {
+		t.Fatalf("repeated value: got %v, want nil", err)
+	}
+	if err := w.Add(1); err == nil {
+		t.Fatalf("decreasing value: got nil, want an error")
+	}
+	if err := w.Close(); err == nil {
+		t.Fatalf("close after error: got nil, want an error")
+	}
+}
+
+func TestMappedSetCorrupt(t *testing.T) {
+	t.Parallel()
+
+	src := Of[uint64](1, 2, 3)
+	var b bytes.Buffer
+	WriteMapped(&b, &src)
+	data := b.Bytes()
+
+	tests := map[string]struct {
+		corrupt       func(b []byte) []byte
+		wantErr       error
+		wantVerifyErr bool
+	}{
+		"magic": {
+			corrupt: func(b []byte) []byte { b[0] = 'X'; return b },
+			wantErr: ErrCorrupt,
+		},
+		"version": {
+			corrupt: func(b []byte) []byte { binary.LittleEndian.PutUint32(b[8:], 2); return b },
+			wantErr: ErrVersion,
+		},
+		"block length": {
+			corrupt: func(b []byte) []byte { binary.LittleEndian.PutUint32(b[12:], 2); return b },
+			wantErr: ErrCorrupt,
+		},
+		"truncated": {
+			corrupt: func(b []byte) []byte { return b[:len(b)-1] },
+			wantErr: ErrCorrupt,
+		},
+		"too short": {
+			corrupt: func(b []byte) []byte { return b[:10] },
+			wantErr: ErrCorrupt,
+		},
+		"index": {
+			corrupt: func(b []byte) []byte { b[mappedHeaderSize+3*8] ^= 1; return b },
+			wantErr: ErrCorrupt,
+		},
+		"value": {
+			corrupt:       func(b []byte) []byte { b[mappedHeaderSize+8] ^= 1; return b },
+			wantVerifyErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "set")
+			if err := os.WriteFile(path, tt.corrupt(slices.Clone(data)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			s, err := OpenMapped(path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("open: got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer s.Close()
+			if err := s.Verify(); (err != nil) != tt.wantVerifyErr || err != nil && !errors.Is(err, ErrCorrupt) {
+				t.Fatalf("verify: got %v, want error %v", err, tt.wantVerifyErr)
+			}
+		})
+	}
+}
+
+func BenchmarkMappedSetContains(b *testing.B) {
+	for _, n := range []int{1 << 10, 1 << 20} {
+		src := Set[uint64]{}
+		r := rand.New(rand.NewSource(1))
+		for src.Len() < n {
+			src.Add(r.Uint64())
+		}
+		keys := src.ToSlice()
+		s, err := OpenMapped(writeTestMapped(b, &src))
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("n=%d/Set", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				src.Contains(keys[i%n])
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/Mapped", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s.Contains(keys[i%n])
+			}
+		})
+		s.Close()
+	}
+}
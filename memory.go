@@ -0,0 +1,81 @@
+package set
+
+import "unsafe"
+
+// Stats describes the memory used by a set.
+type Stats struct {
+	// Len is the number of elements in the set.
+	Len int
+	// Peak is the most elements the set has held, or been sized for by
+	// WithCap or Reserve, since its storage was last allocated.
+	// The storage doesn't shrink below what Peak elements need until
+	// Compact or Reset is called.
+	Peak int
+	// Bytes is an estimate of the memory used by the set's storage,
+	// following the map layout of the Go release the program is built
+	// with. It doesn't include memory referenced by the elements,
+	// such as the contents of strings.
+	Bytes int
+}
+
+// Stats returns statistics about the memory used by s.
+func (s *Set[Elem]) Stats() Stats {
+	s.notePeak()
+	var zero Elem
+	return Stats{
+		Len:   len(s.m),
+		Peak:  s.peak,
+		Bytes: mapBytes(s.peak, int(unsafe.Sizeof(zero))),
+	}
+}
+
+// Compact reallocates the storage of s to fit its current elements,
+// releasing the memory left behind by removals.
+// It takes O(n) time, so it is best called after large removals
+// rather than after every one.
+func (s *Set[Elem]) Compact() {
+	s.notePeak()
+	if s.peak == len(s.m) {
+		return
+	}
+	s.resize(len(s.m))
+}
+
+// Reserve grows the storage of s, if necessary, so that n more elements
+// can be added without reallocating it.
+func (s *Set[Elem]) Reserve(n int) {
+	if n < 0 {
+		panic("reserved count has to be non-negative")
+	}
+	s.notePeak()
+	if len(s.m)+n <= s.peak && s.m != nil {
+		return
+	}
+	s.resize(len(s.m) + n)
+}
+
+// Reset removes all elements from s and releases its storage,
+// leaving s as if it were the zero value.
+func (s *Set[Elem]) Reset() {
+	s.m = nil
+	s.peak = 0
+}
+
+// resize moves the elements of s to a new map sized for size elements.
+func (s *Set[Elem]) resize(size int) {
+	m := make(map[Elem]struct{}, size)
+	for k := range s.m {
+		m[k] = struct{}{}
+	}
+	s.m = m
+	s.peak = size
+}
+
+// notePeak records the current length of s in s.peak.
+// It must be called before removing elements, as m keeps the memory
+// it needed at its largest.
+func (s *Set[Elem]) notePeak() {
+	if len(s.m) > s.peak {
+		s.peak = len(s.m)
+	}
+}
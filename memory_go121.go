@@ -0,0 +1,28 @@
+//go:build !go1.24
+
+package set
+
+// mapBytes estimates the memory used by a map[Elem]struct{} sized for n
+// elements of size elemSize. It follows the map layout of Go 1.23 and
+// earlier: 2^B buckets of 8 slots, each with 8 hash bytes and an
+// overflow pointer, at most 6 elements per bucket on average, and
+// 2^(B-4) spare overflow buckets allocated with tables of 16 buckets
+// or more.
+func mapBytes(n, elemSize int) int {
+	const (
+		header     = 48
+		bucketSize = 8
+	)
+	if n == 0 {
+		return header
+	}
+	b := 0
+	for n > bucketSize && n > 6<<b {
+		b++
+	}
+	buckets := 1 << b
+	if b >= 4 {
+		buckets += 1 << (b - 4)
+	}
+	return header + buckets*(bucketSize+bucketSize*elemSize+8)
+}
@@ -0,0 +1,32 @@
+//go:build !go1.24
+
+package set
+
+import "testing"
+
+func TestMapBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		n    int
+		want int
+	}{
+		"empty":            {n: 0, want: 48},
+		"one bucket":       {n: 8, want: 48 + 80},
+		"two buckets":      {n: 9, want: 48 + 2*80},
+		"full table":       {n: 12, want: 48 + 2*80},
+		"grown":            {n: 13, want: 48 + 4*80},
+		"overflow buckets": {n: 100, want: 48 + 34*80},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := mapBytes(tt.n, 8); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
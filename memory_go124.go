@@ -0,0 +1,28 @@
+//go:build go1.24
+
+package set
+
+import "math/bits"
+
+// mapBytes estimates the memory used by a map[Elem]struct{} sized for n
+// elements of size elemSize. It follows the map layout of Go 1.24 and
+// later: slots are grouped by 8 with a control word per group, tables
+// are at most 7/8 full, and a map with no more than 8 elements uses a
+// single group. Builds with GOEXPERIMENT=noswissmap use the layout in
+// memory_go121.go instead, which this doesn't model.
+func mapBytes(n, elemSize int) int {
+	const (
+		header    = 48
+		groupSize = 8
+		maxLoad   = 7
+	)
+	if n == 0 {
+		return header
+	}
+	slots := groupSize
+	if n > groupSize {
+		need := (n*groupSize + maxLoad - 1) / maxLoad
+		slots = 1 << bits.Len(uint(need-1))
+	}
+	return header + slots*elemSize + slots/groupSize*8
+}
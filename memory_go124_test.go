@@ -0,0 +1,31 @@
+//go:build go1.24
+
+package set
+
+import "testing"
+
+func TestMapBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		n    int
+		want int
+	}{
+		"empty":      {n: 0, want: 48},
+		"one group":  {n: 8, want: 48 + 8*8 + 8},
+		"two groups": {n: 9, want: 48 + 16*8 + 2*8},
+		"full table": {n: 14, want: 48 + 16*8 + 2*8},
+		"grown":      {n: 15, want: 48 + 32*8 + 4*8},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := mapBytes(tt.n, 8); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package set
+
+import "testing"
+
+func TestSetStats(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		ops      func(s *Set[int])
+		wantLen  int
+		wantPeak int
+	}{
+		"zero value": {
+			ops:      func(s *Set[int]) {},
+			wantLen:  0,
+			wantPeak: 0,
+		},
+		"add": {
+			ops:      func(s *Set[int]) { s.Add(1, 2, 3) },
+			wantLen:  3,
+			wantPeak: 3,
+		},
+		"remove keeps peak": {
+			ops: func(s *Set[int]) {
+				s.Add(1, 2, 3)
+				s.Remove(1, 2)
+			},
+			wantLen:  1,
+			wantPeak: 3,
+		},
+		"retain keeps peak": {
+			ops: func(s *Set[int]) {
+				s.Add(1, 2, 3, 4)
+				s.Retain(func(v int) bool { return v == 1 })
+			},
+			wantLen:  1,
+			wantPeak: 4,
+		},
+		"clear keeps peak": {
+			ops: func(s *Set[int]) {
+				s.Add(1, 2, 3)
+				s.Clear()
+				s.Add(4)
+			},
+			wantLen:  1,
+			wantPeak: 3,
+		},
+		"pop keeps peak": {
+			ops: func(s *Set[int]) {
+				s.Add(1, 2)
+				s.Pop()
+			},
+			wantLen:  1,
+			wantPeak: 2,
+		},
+		"tx remove keeps peak": {
+			ops: func(s *Set[int]) {
+				s.Add(1, 2)
+				tx := s.Begin()
+				tx.Remove(1)
+				tx.Commit()
+			},
+			wantLen:  1,
+			wantPeak: 2,
+		},
+		"compact": {
+			ops: func(s *Set[int]) {
+				s.Add(1, 2, 3)
+				s.Remove(1, 2)
+				s.Compact()
+			},
+			wantLen:  1,
+			wantPeak: 1,
+		},
+		"reserve": {
+			ops: func(s *Set[int]) {
+				s.Add(1)
+				s.Reserve(10)
+			},
+			wantLen:  1,
+			wantPeak: 11,
+		},
+		"reserve within peak": {
+			ops: func(s *Set[int]) {
+				s.Add(1, 2, 3)
+				s.Remove(1, 2)
+				s.Reserve(1)
+			},
+			wantLen:  1,
+			wantPeak: 3,
+		},
+		"reset": {
+			ops: func(s *Set[int]) {
+				s.Add(1, 2, 3)
+				s.Reset()
+			},
+			wantLen:  0,
+			wantPeak: 0,
+		},
+		"with cap": {
+			ops: func(s *Set[int]) {
+				*s = WithCap[int](100)
+				s.Add(1)
+			},
+			wantLen:  1,
+			wantPeak: 100,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := Set[int]{}
+			tt.ops(&s)
+			got := s.Stats()
+			if got.Len != tt.wantLen || got.Len != s.Len() {
+				t.Fatalf("len: got %v, want %v", got.Len, tt.wantLen)
+			}
+			if got.Peak != tt.wantPeak {
+				t.Fatalf("peak: got %v, want %v", got.Peak, tt.wantPeak)
+			}
+			if want := mapBytes(tt.wantPeak, 8); got.Bytes != want {
+				t.Fatalf("bytes: got %v, want %v", got.Bytes, want)
+			}
+		})
+	}
+}
+
+func TestSetCompactKeepsElements(t *testing.T) {
+	t.Parallel()
+
+	s := Set[int]{}
+	for i := 0; i < 1000; i++ {
+		s.Add(i)
+	}
+	s.Retain(func(v int) bool { return v%100 == 0 })
+	before := s.Stats().Bytes
+	s.Compact()
+	if got := s.Stats().Bytes; got >= before {
+		t.Fatalf("bytes after compact: got %v, want less than %v", got, before)
+	}
+	want := Of(0, 100, 200, 300, 400, 500, 600, 700, 800, 900)
+	if !s.Equal(want) {
+		t.Fatalf("got %v, want %v", s, want)
+	}
+}
+
+func TestSetReuseCapacity(t *testing.T) {
+	tests := map[string]func(s *Set[int]){
+		"clear": func(s *Set[int]) {
+			for i := 0; i < 100; i++ {
+				s.Add(i)
+			}
+			s.Clear()
+		},
+		"reserve": func(s *Set[int]) {
+			s.Reserve(100)
+		},
+	}
+
+	for name, prepare := range tests {
+		prepare := prepare
+		t.Run(name, func(t *testing.T) {
+			s := Set[int]{}
+			prepare(&s)
+			allocs := testing.AllocsPerRun(10, func() {
+				for i := 0; i < 100; i++ {
+					s.Add(i)
+				}
+				s.Clear()
+			})
+			if allocs != 0 {
+				t.Fatalf("got %v allocations, want 0", allocs)
+			}
+		})
+	}
+}
+
+func TestSetReserveNegative(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "reserved count has to be non-negative" {
+			t.Fatalf("got %v, want %v", r, "reserved count has to be non-negative")
+		}
+	}()
+	s := Set[int]{}
+	s.Reserve(-1)
+}
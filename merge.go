@@ -0,0 +1,54 @@
+package set
+
+// Resolver decides the outcome of a merge conflict on v.
+// It returns true to keep v in the merged result and false to drop it.
+type Resolver[Elem comparable] func(v Elem) bool
+
+// PreferAdd is a Resolver that resolves every conflict by keeping the element.
+func PreferAdd[Elem comparable](Elem) bool {
+	return true
+}
+
+// PreferRemove is a Resolver that resolves every conflict by dropping the element.
+func PreferRemove[Elem comparable](Elem) bool {
+	return false
+}
+
+// Merge3 merges the changes that ours and theirs made to base.
+// An element is in the result if it is in base and neither side removed it,
+// or if either side added it.
+//
+// Because both sides are compared against the same base, no element can be
+// added by one side and removed by the other, so a three-way merge of sets
+// never conflicts. Use MergeDeltas to merge changes that were recorded
+// independently and may conflict.
+func Merge3[Elem comparable](base, ours, theirs Set[Elem]) Set[Elem] {
+	d, _ := MergeDeltas(Diff(base, ours), Diff(base, theirs), PreferAdd[Elem])
+	r := base.Clone()
+	r.Apply(d)
+	return r
+}
+
+// MergeDeltas merges two deltas into one that makes the changes of both.
+// An element added by one delta and removed by the other is a conflict;
+// resolve decides whether the merged delta adds or removes it.
+// The conflicting elements are returned as a set.
+func MergeDeltas[Elem comparable](ours, theirs Delta[Elem], resolve Resolver[Elem]) (Delta[Elem], Set[Elem]) {
+	addedRemoved := Intersect(&ours.Added, &theirs.Removed)
+	removedAdded := Intersect(&ours.Removed, &theirs.Added)
+	conflicts := Union(&addedRemoved, &removedAdded)
+	added := Union(&ours.Added, &theirs.Added)
+	removed := Union(&ours.Removed, &theirs.Removed)
+	r := Delta[Elem]{
+		Added:   Difference(&added, &conflicts),
+		Removed: Difference(&removed, &conflicts),
+	}
+	for k := range conflicts.m {
+		if resolve(k) {
+			r.Added.m[k] = struct{}{}
+		} else {
+			r.Removed.m[k] = struct{}{}
+		}
+	}
+	return r, conflicts
+}
@@ -0,0 +1,100 @@
+package set
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		base   Set[string]
+		ours   Set[string]
+		theirs Set[string]
+		want   Set[string]
+	}{
+		"initialization and empty": {
+			base:   Set[string]{},
+			ours:   Set[string]{},
+			theirs: Set[string]{},
+			want:   Of[string](),
+		},
+		"no changes": {
+			base:   Of("a", "b"),
+			ours:   Of("a", "b"),
+			theirs: Of("a", "b"),
+			want:   Of("a", "b"),
+		},
+		"one side changed": {
+			base:   Of("a", "b"),
+			ours:   Of("a", "c"),
+			theirs: Of("a", "b"),
+			want:   Of("a", "c"),
+		},
+		"both sides changed": {
+			base:   Of("a", "b", "c"),
+			ours:   Of("a", "c", "d"),
+			theirs: Of("a", "b", "e"),
+			want:   Of("a", "d", "e"),
+		},
+		"same change on both sides": {
+			base:   Of("a", "b"),
+			ours:   Of("b", "c"),
+			theirs: Of("b", "c"),
+			want:   Of("b", "c"),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got := Merge3(tt.base, tt.ours, tt.theirs)
+			if !tt.want.Equal(got) {
+				t.Fatalf("got %v, want %v", got.ToSlice(), tt.want.ToSlice())
+			}
+		})
+	}
+}
+
+func TestMergeDeltas(t *testing.T) {
+	t.Parallel()
+
+	ours := Delta[string]{Added: Of("a", "b"), Removed: Of("c", "d")}
+	theirs := Delta[string]{Added: Of("c", "e"), Removed: Of("a")}
+
+	tests := map[string]struct {
+		resolve     Resolver[string]
+		wantAdded   Set[string]
+		wantRemoved Set[string]
+	}{
+		"prefer add": {
+			resolve:     PreferAdd[string],
+			wantAdded:   Of("a", "b", "c", "e"),
+			wantRemoved: Of("d"),
+		},
+		"prefer remove": {
+			resolve:     PreferRemove[string],
+			wantAdded:   Of("b", "e"),
+			wantRemoved: Of("a", "c", "d"),
+		},
+		"callback": {
+			resolve:     func(v string) bool { return v == "a" },
+			wantAdded:   Of("a", "b", "e"),
+			wantRemoved: Of("c", "d"),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, conflicts := MergeDeltas(ours, theirs, tt.resolve)
+			if want := Of("a", "c"); !want.Equal(conflicts) {
+				t.Fatalf("conflicts: got %v, want %v", conflicts.ToSlice(), want.ToSlice())
+			}
+			if !tt.wantAdded.Equal(got.Added) {
+				t.Fatalf("added: got %v, want %v", got.Added.ToSlice(), tt.wantAdded.ToSlice())
+			}
+			if !tt.wantRemoved.Equal(got.Removed) {
+				t.Fatalf("removed: got %v, want %v", got.Removed.ToSlice(), tt.wantRemoved.ToSlice())
+			}
+		})
+	}
+}
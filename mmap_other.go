@@ -0,0 +1,20 @@
+//go:build !unix
+
+package set
+
+import (
+	"io"
+	"os"
+)
+
+// mmapFile reads the first size bytes of f, on platforms where files
+// can't be mapped into memory.
+func mmapFile(f *os.File, size int) ([]byte, error) {
+	b := make([]byte, size)
+	_, err := io.ReadFull(f, b)
+	return b, err
+}
+
+func munmap(b []byte) error {
+	return nil
+}
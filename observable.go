@@ -0,0 +1,270 @@
+package set
+
+import "sync"
+
+// Event describes a change in the membership of an ObservableSet.
+// A single operation produces at most one event,
+// so bulk operations are reported as one batch.
+// Subscribers must not modify the slices.
+type Event[Elem comparable] struct {
+	Added   []Elem
+	Removed []Elem
+}
+
+// ObservableSet is a set that notifies subscribers whenever an operation
+// changes its membership. Operations that leave the membership unchanged
+// produce no event. It is safe for concurrent use; events are delivered
+// in the order the changes were made.
+// The zero value is an empty set with no subscribers, ready to use.
+type ObservableSet[Elem comparable] struct {
+	// mu guards set, subs and next.
+	mu   sync.Mutex
+	set  Set[Elem]
+	subs []*subscriber[Elem]
+	// next is the sequence number of the next event. Events are numbered
+	// while mu is held and delivered after it is released, in order.
+	next uint64
+	// deliverMu guards delivered and is held while an event is delivered;
+	// turn signals that delivered has changed.
+	deliverMu sync.Mutex
+	turn      sync.Cond
+	delivered uint64
+}
+
+type subscriber[Elem comparable] struct {
+	f    func(Event[Elem])
+	ch   chan Event[Elem]
+	done chan struct{}
+	once sync.Once
+}
+
+// NewObservable returns a new observable set containing the elements of s.
+func NewObservable[Elem comparable](s Set[Elem]) *ObservableSet[Elem] {
+	return &ObservableSet[Elem]{set: s.Clone()}
+}
+
+// Subscribe registers f to be called synchronously, in the goroutine
+// making the change, for every event. f is called after o is unlocked, so
+// it may read o, and other goroutines may change o meanwhile; their events
+// are delivered after f returns. f must not modify o.
+// The returned function unsubscribes f; it may be called from within f.
+func (o *ObservableSet[Elem]) Subscribe(f func(Event[Elem])) (unsubscribe func()) {
+	sub := &subscriber[Elem]{f: f, done: make(chan struct{})}
+	o.subscribe(sub)
+	return func() {
+		o.unsubscribe(sub)
+	}
+}
+
+// SubscribeChan returns a channel with the given buffer size that receives
+// every event. A change blocks while the channel's buffer is full,
+// though o can still be read.
+// The returned function unsubscribes and closes the channel.
+func (o *ObservableSet[Elem]) SubscribeChan(buffer int) (<-chan Event[Elem], func()) {
+	sub := &subscriber[Elem]{ch: make(chan Event[Elem], buffer), done: make(chan struct{})}
+	o.subscribe(sub)
+	return sub.ch, func() {
+		o.unsubscribe(sub)
+		o.deliverMu.Lock()
+		defer o.deliverMu.Unlock()
+		sub.once.Do(func() {
+			close(sub.ch)
+		})
+	}
+}
+
+// Add adds elements to a set.
+func (o *ObservableSet[Elem]) Add(v ...Elem) {
+	for _, v := range v {
+		if v != v {
+			panic("element in set has to be equal to itself")
+		}
+	}
+	o.mu.Lock()
+	var ev Event[Elem]
+	for _, v := range v {
+		if !o.set.Contains(v) {
+			o.set.Add(v)
+			ev.Added = append(ev.Added, v)
+		}
+	}
+	o.publish(ev)
+}
+
+// AddSet adds the elements of set s2 to o.
+func (o *ObservableSet[Elem]) AddSet(s2 Interface[Elem]) {
+	o.mu.Lock()
+	var ev Event[Elem]
+	s2.Do(func(k Elem) bool {
+		if !o.set.Contains(k) {
+			o.set.Add(k)
+			ev.Added = append(ev.Added, k)
+		}
+		return true
+	})
+	o.publish(ev)
+}
+
+// Remove removes elements from a set.
+// Elements that are not present are ignored.
+func (o *ObservableSet[Elem]) Remove(v ...Elem) {
+	o.mu.Lock()
+	var ev Event[Elem]
+	for _, v := range v {
+		if o.set.Contains(v) {
+			o.set.Remove(v)
+			ev.Removed = append(ev.Removed, v)
+		}
+	}
+	o.publish(ev)
+}
+
+// RemoveSet removes the elements of set s2 from o.
+// Elements present in s2 but not o are ignored.
+func (o *ObservableSet[Elem]) RemoveSet(s2 Interface[Elem]) {
+	o.mu.Lock()
+	var ev Event[Elem]
+	s2.Do(func(k Elem) bool {
+		if o.set.Contains(k) {
+			o.set.Remove(k)
+			ev.Removed = append(ev.Removed, k)
+		}
+		return true
+	})
+	o.publish(ev)
+}
+
+// Retain deletes any elements from o for which keep returns false.
+// keep must not call methods on o.
+func (o *ObservableSet[Elem]) Retain(keep func(Elem) bool) {
+	o.mu.Lock()
+	var ev Event[Elem]
+	o.set.Retain(func(v Elem) bool {
+		if keep(v) {
+			return true
+		}
+		ev.Removed = append(ev.Removed, v)
+		return false
+	})
+	o.publish(ev)
+}
+
+// Clear removes all elements from o, leaving it empty.
+func (o *ObservableSet[Elem]) Clear() {
+	o.mu.Lock()
+	ev := Event[Elem]{Removed: o.set.ToSlice()}
+	o.set.Clear()
+	o.publish(ev)
+}
+
+// Pop removes and returns an arbitrary element from o.
+func (o *ObservableSet[Elem]) Pop() (Elem, bool) {
+	o.mu.Lock()
+	v, ok := o.set.Pop()
+	var ev Event[Elem]
+	if ok {
+		ev.Removed = []Elem{v}
+	}
+	o.publish(ev)
+	return v, ok
+}
+
+// Contains reports whether v is in the set.
+func (o *ObservableSet[Elem]) Contains(v Elem) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.set.Contains(v)
+}
+
+// Len returns the number of elements in o.
+func (o *ObservableSet[Elem]) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.set.Len()
+}
+
+// Do calls f on every element in the set o,
+// stopping if f returns false.
+// f must not call methods on o.
+// f will be called on values in an indeterminate order.
+func (o *ObservableSet[Elem]) Do(f func(Elem) bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.set.Do(f)
+}
+
+// ToSlice returns the elements in the set o as a slice.
+// The values will be in an indeterminate order.
+func (o *ObservableSet[Elem]) ToSlice() []Elem {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.set.ToSlice()
+}
+
+// Snapshot returns a copy of the current contents of o.
+func (o *ObservableSet[Elem]) Snapshot() Set[Elem] {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.set.Clone()
+}
+
+func (o *ObservableSet[Elem]) subscribe(sub *subscriber[Elem]) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	subs := make([]*subscriber[Elem], len(o.subs), len(o.subs)+1)
+	copy(subs, o.subs)
+	o.subs = append(subs, sub)
+}
+
+func (o *ObservableSet[Elem]) unsubscribe(sub *subscriber[Elem]) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	subs := make([]*subscriber[Elem], 0, len(o.subs))
+	for _, s := range o.subs {
+		if s != sub {
+			subs = append(subs, s)
+		} else {
+			close(s.done)
+		}
+	}
+	o.subs = subs
+}
+
+// publish delivers ev to the current subscribers.
+// o.mu must be held; publish releases it before delivering ev.
+func (o *ObservableSet[Elem]) publish(ev Event[Elem]) {
+	if len(ev.Added) == 0 && len(ev.Removed) == 0 {
+		o.mu.Unlock()
+		return
+	}
+	subs := o.subs
+	seq := o.next
+	o.next++
+	if o.turn.L == nil {
+		o.turn.L = &o.deliverMu
+	}
+	o.mu.Unlock()
+
+	o.deliverMu.Lock()
+	defer o.deliverMu.Unlock()
+	for o.delivered != seq {
+		o.turn.Wait()
+	}
+	defer o.turn.Broadcast()
+	o.delivered++
+	for _, sub := range subs {
+		select {
+		case <-sub.done:
+			continue
+		default:
+		}
+		if sub.f != nil {
+			sub.f(ev)
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		case <-sub.done:
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package set
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestObservableSetEvents(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		start       Set[int]
+		op          func(o *ObservableSet[int])
+		want        Set[int]
+		wantAdded   []int
+		wantRemoved []int
+		wantEvents  int
+	}{
+		"add new elements": {
+			start:      Of(1),
+			op:         func(o *ObservableSet[int]) { o.Add(1, 2, 3) },
+			want:       Of(1, 2, 3),
+			wantAdded:  []int{2, 3},
+			wantEvents: 1,
+		},
+		"add existing elements": {
+			start:      Of(1, 2),
+			op:         func(o *ObservableSet[int]) { o.Add(1, 2) },
+			want:       Of(1, 2),
+			wantEvents: 0,
+		},
+		"add set": {
+			start: Of(1),
+			op: func(o *ObservableSet[int]) {
+				s := Of(1, 2)
+				o.AddSet(&s)
+			},
+			want:       Of(1, 2),
+			wantAdded:  []int{2},
+			wantEvents: 1,
+		},
+		"remove": {
+			start:       Of(1, 2),
+			op:          func(o *ObservableSet[int]) { o.Remove(2, 3) },
+			want:        Of(1),
+			wantRemoved: []int{2},
+			wantEvents:  1,
+		},
+		"remove set": {
+			start: Of(1, 2, 3),
+			op: func(o *ObservableSet[int]) {
+				s := Of(1, 3, 4)
+				o.RemoveSet(&s)
+			},
+			want:        Of(2),
+			wantRemoved: []int{1, 3},
+			wantEvents:  1,
+		},
+		"retain": {
+			start:       Of(1, 2, 3, 4),
+			op:          func(o *ObservableSet[int]) { o.Retain(func(v int) bool { return v%2 == 0 }) },
+			want:        Of(2, 4),
+			wantRemoved: []int{1, 3},
+			wantEvents:  1,
+		},
+		"clear": {
+			start:       Of(1, 2),
+			op:          func(o *ObservableSet[int]) { o.Clear() },
+			want:        Of[int](),
+			wantRemoved: []int{1, 2},
+			wantEvents:  1,
+		},
+		"clear empty": {
+			start:      Set[int]{},
+			op:         func(o *ObservableSet[int]) { o.Clear() },
+			want:       Of[int](),
+			wantEvents: 0,
+		},
+		"pop": {
+			start:       Of(1),
+			op:          func(o *ObservableSet[int]) { o.Pop() },
+			want:        Of[int](),
+			wantRemoved: []int{1},
+			wantEvents:  1,
+		},
+		"pop empty": {
+			start:      Set[int]{},
+			op:         func(o *ObservableSet[int]) { o.Pop() },
+			want:       Of[int](),
+			wantEvents: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			o := NewObservable(tt.start)
+			var events []Event[int]
+			o.Subscribe(func(ev Event[int]) {
+				events = append(events, ev)
+			})
+			tt.op(o)
+			if got := o.Snapshot(); !tt.want.Equal(got) {
+				t.Fatalf("got %v, want %v", got.ToSlice(), tt.want.ToSlice())
+			}
+			if len(events) != tt.wantEvents {
+				t.Fatalf("events: got %v, want %v", len(events), tt.wantEvents)
+			}
+			var added, removed []int
+			for _, ev := range events {
+				added = append(added, ev.Added...)
+				removed = append(removed, ev.Removed...)
+			}
+			slices.Sort(added)
+			slices.Sort(removed)
+			if !slices.Equal(added, tt.wantAdded) {
+				t.Fatalf("added: got %v, want %v", added, tt.wantAdded)
+			}
+			if !slices.Equal(removed, tt.wantRemoved) {
+				t.Fatalf("removed: got %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestObservableSetUnsubscribe(t *testing.T) {
+	t.Parallel()
+
+	var o ObservableSet[int]
+	var calledTimes int
+	unsubscribe := o.Subscribe(func(Event[int]) {
+		calledTimes++
+	})
+	o.Add(1)
+	unsubscribe()
+	unsubscribe()
+	o.Add(2)
+	if calledTimes != 1 {
+		t.Fatalf("calledTimes: got %v, want %v", calledTimes, 1)
+	}
+}
+
+func TestObservableSetUnsubscribeWithinCallback(t *testing.T) {
+	t.Parallel()
+
+	var o ObservableSet[int]
+	var calledTimes int
+	var unsubscribe func()
+	unsubscribe = o.Subscribe(func(Event[int]) {
+		calledTimes++
+		unsubscribe()
+	})
+	o.Add(1)
+	o.Add(2)
+	if calledTimes != 1 {
+		t.Fatalf("calledTimes: got %v, want %v", calledTimes, 1)
+	}
+}
+
+func TestObservableSetSubscribeChan(t *testing.T) {
+	t.Parallel()
+
+	var o ObservableSet[int]
+	ch, unsubscribe := o.SubscribeChan(2)
+	o.Add(1, 2)
+	o.Remove(1)
+	unsubscribe()
+	o.Add(3)
+
+	var events []Event[int]
+	for ev := range ch {
+		events = append(events, ev)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events: got %v, want %v", len(events), 2)
+	}
+	if len(events[0].Added) != 2 || !slices.Equal(events[1].Removed, []int{1}) {
+		t.Fatalf("got %v", events)
+	}
+}
+
+func TestObservableSetUnsubscribeUnblocksChan(t *testing.T) {
+	t.Parallel()
+
+	var o ObservableSet[int]
+	_, unsubscribe := o.SubscribeChan(0)
+	done := make(chan struct{})
+	go func() {
+		o.Add(1)
+		close(done)
+	}()
+	unsubscribe()
+	<-done
+	if !o.Contains(1) {
+		t.Fatalf("got %v, want %v", o.ToSlice(), []int{1})
+	}
+}
+
+func TestObservableSetReadDuringDelivery(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(o *ObservableSet[int], entered, release chan struct{}){
+		"callback": func(o *ObservableSet[int], entered, release chan struct{}) {
+			o.Subscribe(func(ev Event[int]) {
+				if slices.Equal(ev.Added, []int{1}) {
+					close(entered)
+					<-release
+					o.Len()
+				}
+			})
+		},
+		// The consumer reads o while a writer waits on the full channel.
+		"chan": func(o *ObservableSet[int], entered, release chan struct{}) {
+			ch, _ := o.SubscribeChan(0)
+			go func() {
+				<-ch
+				close(entered)
+				<-release
+				o.Contains(1)
+				for range ch {
+				}
+			}()
+		},
+	}
+	for name, subscribe := range tests {
+		subscribe := subscribe
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var o ObservableSet[int]
+			entered, release := make(chan struct{}), make(chan struct{})
+			subscribe(&o, entered, release)
+
+			first := make(chan struct{})
+			go func() {
+				o.Add(1)
+				close(first)
+			}()
+			<-entered
+			// More writers wait for the first event to be delivered.
+			second, third := make(chan struct{}), make(chan struct{})
+			go func() {
+				o.Add(2)
+				close(second)
+			}()
+			go func() {
+				o.Add(3)
+				close(third)
+			}()
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+			for _, done := range []chan struct{}{first, second, third} {
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Fatal("deadlock: reading o during delivery blocked the writers")
+				}
+			}
+			if got := o.Len(); got != 3 {
+				t.Fatalf("got %v, want %v", got, 3)
+			}
+		})
+	}
+}
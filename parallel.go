@@ -0,0 +1,149 @@
+package set
+
+import (
+	"maps"
+	"runtime"
+	"sync"
+)
+
+// minParallel is the number of elements below which the parallel
+// operations fall back to their sequential versions, because starting
+// goroutines costs more than they save. See BenchmarkParallelIntersect.
+var minParallel = 1 << 14
+
+// ParallelIntersect is like Intersect, but splits the work across workers
+// goroutines. If workers is not positive, it uses runtime.GOMAXPROCS(0).
+func ParallelIntersect[Elem comparable](s1, s2 *Set[Elem], workers int) Set[Elem] {
+	if s1.Len() > s2.Len() {
+		s1, s2 = s2, s1
+	}
+	workers = numWorkers(workers, s1.Len())
+	if workers == 1 {
+		return Intersect(s1, s2)
+	}
+	parts := parallelFilter(s1.ToSlice(), workers, s2.Contains)
+	r := WithCap[Elem](partsLen(parts))
+	for _, part := range parts {
+		for _, v := range part {
+			r.m[v] = struct{}{}
+		}
+	}
+	return r
+}
+
+// ParallelUnion is like Union, but splits the work across workers
+// goroutines. If workers is not positive, it uses runtime.GOMAXPROCS(0).
+func ParallelUnion[Elem comparable](s1, s2 *Set[Elem], workers int) Set[Elem] {
+	if s1.Len() < s2.Len() {
+		s1, s2 = s2, s1
+	}
+	workers = numWorkers(workers, s2.Len())
+	if workers == 1 {
+		return Union(s1, s2)
+	}
+	parts := parallelFilter(s2.ToSlice(), workers, func(v Elem) bool {
+		return !s1.Contains(v)
+	})
+	r := cloneMap(s1)
+	for _, part := range parts {
+		for _, v := range part {
+			r.m[v] = struct{}{}
+		}
+	}
+	return r
+}
+
+// ParallelDifference is like Difference, but splits the work across
+// workers goroutines. If workers is not positive, it uses
+// runtime.GOMAXPROCS(0).
+func ParallelDifference[Elem comparable](s1, s2 *Set[Elem], workers int) Set[Elem] {
+	workers = numWorkers(workers, s1.Len())
+	if workers == 1 {
+		return Difference(s1, s2)
+	}
+	parts := parallelFilter(s1.ToSlice(), workers, s2.Contains)
+	r := cloneMap(s1)
+	for _, part := range parts {
+		for _, v := range part {
+			delete(r.m, v)
+		}
+	}
+	return r
+}
+
+// ParallelRetain is like Retain, but calls keep from workers goroutines
+// at once, so keep must be safe for concurrent use. If workers is not
+// positive, it uses runtime.GOMAXPROCS(0).
+func (s *Set[Elem]) ParallelRetain(keep func(Elem) bool, workers int) {
+	workers = numWorkers(workers, s.Len())
+	if workers == 1 {
+		s.Retain(keep)
+		return
+	}
+	parts := parallelFilter(s.ToSlice(), workers, func(v Elem) bool {
+		return !keep(v)
+	})
+	s.notePeak()
+	for _, part := range parts {
+		for _, v := range part {
+			delete(s.m, v)
+		}
+	}
+}
+
+// cloneMap returns a copy of s. It uses maps.Clone, which copies the
+// map's storage without rehashing every element.
+func cloneMap[Elem comparable](s *Set[Elem]) Set[Elem] {
+	return Set[Elem]{m: maps.Clone(s.m), peak: len(s.m)}
+}
+
+func numWorkers(workers, n int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if n < minParallel || n < 2 {
+		return 1
+	}
+	if workers > n {
+		workers = n
+	}
+	return workers
+}
+
+// parallelFilter splits elems into one chunk per worker and returns,
+// for each chunk, the elements for which pred returns true.
+func parallelFilter[Elem comparable](elems []Elem, workers int, pred func(Elem) bool) [][]Elem {
+	parts := make([][]Elem, workers)
+	size := (len(elems) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for i := range parts {
+		lo, hi := i*size, (i+1)*size
+		if hi > len(elems) {
+			hi = len(elems)
+		}
+		if lo >= hi {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, chunk []Elem) {
+			defer wg.Done()
+			var part []Elem
+			for _, v := range chunk {
+				if pred(v) {
+					part = append(part, v)
+				}
+			}
+			parts[i] = part
+		}(i, elems[lo:hi])
+	}
+	wg.Wait()
+	return parts
+}
+
+func partsLen[Elem comparable](parts [][]Elem) int {
+	n := 0
+	for _, part := range parts {
+		n += len(part)
+	}
+	return n
+}
@@ -0,0 +1,107 @@
+package set
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		n1, n2  int
+		workers int
+	}{
+		"initialization and empty": {n1: 0, n2: 0, workers: 4},
+		"sequential fallback":      {n1: 100, n2: 50, workers: 4},
+		"parallel":                 {n1: minParallel * 2, n2: minParallel + 7, workers: 4},
+		"parallel default workers": {n1: minParallel + 3, n2: minParallel * 3, workers: 0},
+		"more workers than chunks": {n1: minParallel, n2: minParallel, workers: 1000},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			// s1 holds the multiples of 2 and s2 the multiples of 3.
+			s1, s2 := Set[int]{}, Set[int]{}
+			for i := 0; i < tt.n1; i++ {
+				s1.Add(i * 2)
+			}
+			for i := 0; i < tt.n2; i++ {
+				s2.Add(i * 3)
+			}
+
+			if got, want := ParallelIntersect(&s1, &s2, tt.workers), Intersect(&s1, &s2); !want.Equal(got) {
+				t.Fatalf("intersect: got %v elements, want %v", got.Len(), want.Len())
+			}
+			if got, want := ParallelUnion(&s1, &s2, tt.workers), Union(&s1, &s2); !want.Equal(got) {
+				t.Fatalf("union: got %v elements, want %v", got.Len(), want.Len())
+			}
+			if got, want := ParallelDifference(&s1, &s2, tt.workers), Difference(&s1, &s2); !want.Equal(got) {
+				t.Fatalf("difference: got %v elements, want %v", got.Len(), want.Len())
+			}
+			if got, want := ParallelDifference(&s2, &s1, tt.workers), Difference(&s2, &s1); !want.Equal(got) {
+				t.Fatalf("difference: got %v elements, want %v", got.Len(), want.Len())
+			}
+
+			keep := func(v int) bool { return v%5 != 0 }
+			got, want := s1.Clone(), s1.Clone()
+			got.ParallelRetain(keep, tt.workers)
+			want.Retain(keep)
+			if !want.Equal(got) {
+				t.Fatalf("retain: got %v elements, want %v", got.Len(), want.Len())
+			}
+		})
+	}
+}
+
+// benchmarkParallel compares op with its sequential version at several
+// sizes. The parallel operations always run in parallel here, so the
+// results show where they start to win; minParallel is set from them.
+func benchmarkParallel(b *testing.B, seq, par func(s1, s2 *Set[int])) {
+	defer func(n int) { minParallel = n }(minParallel)
+	minParallel = 0
+
+	for _, size := range []int{1 << 10, 1 << 12, 1 << 14, 1 << 16, 1 << 18, 1 << 20} {
+		s1, s2 := WithCap[int](size), WithCap[int](size)
+		for i := 0; i < size; i++ {
+			s1.Add(i * 2)
+			s2.Add(i * 3)
+		}
+		b.Run(fmt.Sprintf("size=%d/sequential", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				seq(&s1, &s2)
+			}
+		})
+		b.Run(fmt.Sprintf("size=%d/parallel", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				par(&s1, &s2)
+			}
+		})
+	}
+}
+
+func BenchmarkParallelIntersect(b *testing.B) {
+	benchmarkParallel(b,
+		func(s1, s2 *Set[int]) { Intersect(s1, s2) },
+		func(s1, s2 *Set[int]) { ParallelIntersect(s1, s2, 0) })
+}
+
+func BenchmarkParallelUnion(b *testing.B) {
+	benchmarkParallel(b,
+		func(s1, s2 *Set[int]) { Union(s1, s2) },
+		func(s1, s2 *Set[int]) { ParallelUnion(s1, s2, 0) })
+}
+
+func BenchmarkParallelDifference(b *testing.B) {
+	benchmarkParallel(b,
+		func(s1, s2 *Set[int]) { Difference(s1, s2) },
+		func(s1, s2 *Set[int]) { ParallelDifference(s1, s2, 0) })
+}
+
+func BenchmarkParallelRetain(b *testing.B) {
+	keep := func(v int) bool { return v%5 != 0 }
+	benchmarkParallel(b,
+		func(s1, _ *Set[int]) { c := s1.Clone(); c.Retain(keep) },
+		func(s1, _ *Set[int]) { c := s1.Clone(); c.ParallelRetain(keep, 0) })
+}
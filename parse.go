@@ -0,0 +1,226 @@
+package set
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Registry maps names to sets of strings so they can be combined by
+// expressions parsed from text.
+//
+// The expression syntax is
+//
+//	expr   = term { "|" term }
+//	term   = factor { ( "&" | "-" ) factor }
+//	factor = "!" factor | "(" expr ")" | name
+//
+// where | is union, & is intersection, - is difference and ! is the
+// complement against the registry's universe. Names consist of letters,
+// digits, '_' and '.'. For example, "(beta | staff) & !banned".
+type Registry struct {
+	sets     map[string]Interface[string]
+	universe Interface[string]
+}
+
+// SyntaxError describes an error in an expression passed to Registry.Parse.
+type SyntaxError struct {
+	// Column is the position of the error in the source,
+	// counted in characters starting at 1.
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("set: syntax error at column %d: %s", e.Column, e.Msg)
+}
+
+// NewRegistry returns an empty registry. The complement of a set is taken
+// against universe; if universe is nil, expressions using ! are rejected.
+func NewRegistry(universe Interface[string]) *Registry {
+	return &Registry{
+		sets:     map[string]Interface[string]{},
+		universe: universe,
+	}
+}
+
+// Define associates name with the set s, replacing any previous definition.
+// Expressions read s when they are evaluated, so later changes to s
+// are visible to them.
+func (r *Registry) Define(name string, s Interface[string]) {
+	r.sets[name] = s
+}
+
+// Parse parses src and returns the expression it denotes.
+// Every name in src must be defined. The result can answer Contains
+// queries without evaluating the whole expression, or be evaluated
+// with Eval.
+func (r *Registry) Parse(src string) (*Expr[string], error) {
+	p := &parser{r: r, src: []rune(src)}
+	p.next()
+	e := p.expr()
+	if p.err == nil && p.tok != tokEOF {
+		p.fail(p.pos, "unexpected %s", p.describe())
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return e, nil
+}
+
+type token int
+
+const (
+	tokEOF token = iota
+	tokName
+	tokUnion
+	tokIntersect
+	tokMinus
+	tokNot
+	tokLParen
+	tokRParen
+	tokInvalid
+)
+
+var tokens = map[rune]token{
+	'|': tokUnion,
+	'&': tokIntersect,
+	'-': tokMinus,
+	'!': tokNot,
+	'(': tokLParen,
+	')': tokRParen,
+}
+
+type parser struct {
+	r   *Registry
+	src []rune
+	off int
+	err *SyntaxError
+
+	// the current token, its position and, for names, its text
+	tok  token
+	pos  int
+	name string
+}
+
+func isNameRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.'
+}
+
+// next scans the next token.
+func (p *parser) next() {
+	for p.off < len(p.src) && unicode.IsSpace(p.src[p.off]) {
+		p.off++
+	}
+	p.pos = p.off
+	if p.off == len(p.src) {
+		p.tok = tokEOF
+		return
+	}
+	c := p.src[p.off]
+	if tok, ok := tokens[c]; ok {
+		p.tok = tok
+		p.off++
+		return
+	}
+	if !isNameRune(c) {
+		p.tok = tokInvalid
+		p.off++
+		return
+	}
+	for p.off < len(p.src) && isNameRune(p.src[p.off]) {
+		p.off++
+	}
+	p.tok = tokName
+	p.name = string(p.src[p.pos:p.off])
+}
+
+func (p *parser) fail(pos int, format string, args ...any) {
+	if p.err == nil {
+		p.err = &SyntaxError{Column: pos + 1, Msg: fmt.Sprintf(format, args...)}
+	}
+}
+
+// describe returns a description of the current token for error messages.
+func (p *parser) describe() string {
+	switch p.tok {
+	case tokEOF:
+		return "end of expression"
+	case tokName:
+		return fmt.Sprintf("name %q", p.name)
+	default:
+		c := p.src[p.pos]
+		if !utf8.ValidRune(c) || !unicode.IsPrint(c) {
+			return fmt.Sprintf("character %U", c)
+		}
+		return fmt.Sprintf("%q", c)
+	}
+}
+
+func (p *parser) expr() *Expr[string] {
+	e := p.term()
+	for p.err == nil && p.tok == tokUnion {
+		p.next()
+		t := p.term()
+		if p.err != nil {
+			return nil
+		}
+		e = e.Union(t)
+	}
+	return e
+}
+
+func (p *parser) term() *Expr[string] {
+	e := p.factor()
+	for p.err == nil && (p.tok == tokIntersect || p.tok == tokMinus) {
+		op := p.tok
+		p.next()
+		f := p.factor()
+		if p.err != nil {
+			return nil
+		}
+		if op == tokIntersect {
+			e = e.Intersect(f)
+		} else {
+			e = e.Minus(f)
+		}
+	}
+	return e
+}
+
+func (p *parser) factor() *Expr[string] {
+	if p.err != nil {
+		return nil
+	}
+	switch p.tok {
+	case tokNot:
+		pos := p.pos
+		p.next()
+		f := p.factor()
+		if p.r.universe == nil {
+			p.fail(pos, "complement requires a universe")
+			return nil
+		}
+		return Leaf(p.r.universe).Minus(f)
+	case tokLParen:
+		pos := p.pos
+		p.next()
+		e := p.expr()
+		if p.err == nil && p.tok != tokRParen {
+			p.fail(p.pos, "expected ')' to close '(' at column %d, found %s", pos+1, p.describe())
+		}
+		p.next()
+		return e
+	case tokName:
+		s, ok := p.r.sets[p.name]
+		if !ok {
+			p.fail(p.pos, "undefined name %q", p.name)
+			return nil
+		}
+		p.next()
+		return Leaf(s)
+	default:
+		p.fail(p.pos, "expected name, '(' or '!', found %s", p.describe())
+		return nil
+	}
+}
@@ -0,0 +1,162 @@
+package set
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestRegistry() *Registry {
+	universe := Of("ann", "bob", "cat", "dan", "eve")
+	beta, staff, banned := Of("ann", "bob"), Of("bob", "cat", "dan"), Of("bob", "eve")
+	r := NewRegistry(&universe)
+	r.Define("beta", &beta)
+	r.Define("staff", &staff)
+	r.Define("banned", &banned)
+	r.Define("eu.users", &universe)
+	r.Define("équipe", &staff)
+	return r
+}
+
+func TestRegistryParse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		src  string
+		want Set[string]
+	}{
+		"name": {
+			src:  "beta",
+			want: Of("ann", "bob"),
+		},
+		"union": {
+			src:  "beta | staff",
+			want: Of("ann", "bob", "cat", "dan"),
+		},
+		"intersect": {
+			src:  "beta&staff",
+			want: Of("bob"),
+		},
+		"difference": {
+			src:  "staff - banned",
+			want: Of("cat", "dan"),
+		},
+		"complement": {
+			src:  "!staff",
+			want: Of("ann", "eve"),
+		},
+		"double complement": {
+			src:  "!!beta",
+			want: Of("ann", "bob"),
+		},
+		"precedence": {
+			src:  "beta | staff & banned",
+			want: Of("ann", "bob"),
+		},
+		"parentheses and complement": {
+			src:  "(beta | staff) & !banned",
+			want: Of("ann", "cat", "dan"),
+		},
+		"difference is left associative": {
+			src:  "eu.users - staff - beta",
+			want: Of("eve"),
+		},
+	}
+
+	r := newTestRegistry()
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			e, err := r.Parse(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := e.Eval(); !tt.want.Equal(got) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for _, v := range []string{"ann", "bob", "cat", "dan", "eve", "zed"} {
+				if got, want := e.Contains(v), tt.want.Contains(v); got != want {
+					t.Fatalf("contains %v: got %v, want %v", v, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryParseError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		src     string
+		column  int
+		message string
+	}{
+		"empty": {
+			src:     "",
+			column:  1,
+			message: "expected name, '(' or '!', found end of expression",
+		},
+		"undefined name": {
+			src:     "beta | admins",
+			column:  8,
+			message: `undefined name "admins"`,
+		},
+		"missing operand": {
+			src:     "beta &",
+			column:  7,
+			message: "expected name, '(' or '!', found end of expression",
+		},
+		"operator without operand": {
+			src:     "beta | & staff",
+			column:  8,
+			message: "expected name, '(' or '!', found '&'",
+		},
+		"unclosed parenthesis": {
+			src:     "(beta | staff",
+			column:  14,
+			message: "expected ')' to close '(' at column 1, found end of expression",
+		},
+		"trailing input": {
+			src:     "beta staff",
+			column:  6,
+			message: `unexpected name "staff"`,
+		},
+		"invalid character": {
+			src:     "beta + staff",
+			column:  6,
+			message: `unexpected '+'`,
+		},
+		"column counts characters": {
+			src:     "équipe )",
+			column:  8,
+			message: `unexpected ')'`,
+		},
+	}
+
+	r := newTestRegistry()
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			_, err := r.Parse(tt.src)
+			var serr *SyntaxError
+			if !errors.As(err, &serr) {
+				t.Fatalf("got %v, want *SyntaxError", err)
+			}
+			if serr.Column != tt.column || serr.Msg != tt.message {
+				t.Fatalf("got %d: %s, want %d: %s", serr.Column, serr.Msg, tt.column, tt.message)
+			}
+		})
+	}
+}
+
+func TestRegistryParseWithoutUniverse(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(nil)
+	beta := Of("ann")
+	r.Define("beta", &beta)
+	_, err := r.Parse("beta & !beta")
+	want := "set: syntax error at column 8: complement requires a universe"
+	if err == nil || err.Error() != want {
+		t.Fatalf("got %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,267 @@
+package set
+
+// Pair is an element of a Relation.
+type Pair[A, B comparable] struct {
+	From A
+	To   B
+}
+
+// Relation is a binary relation: a set of pairs relating elements of A
+// to elements of B. It indexes the pairs in both directions, so images
+// and preimages take time proportional to the size of their result.
+// Every result that is a set of elements is returned as a Set.
+// The zero value is an empty relation.
+type Relation[A, B comparable] struct {
+	fwd map[A]Set[B]
+	inv map[B]Set[A]
+	n   int
+}
+
+// RelationOf returns a new relation containing the listed pairs.
+func RelationOf[A, B comparable](pairs ...Pair[A, B]) Relation[A, B] {
+	var r Relation[A, B]
+	for _, p := range pairs {
+		r.Add(p.From, p.To)
+	}
+	return r
+}
+
+// Add relates a to b.
+func (r *Relation[A, B]) Add(a A, b B) {
+	if a != a || b != b {
+		panic("element in set has to be equal to itself")
+	}
+	if r.fwd == nil {
+		r.fwd = map[A]Set[B]{}
+		r.inv = map[B]Set[A]{}
+	}
+	bs := r.fwd[a]
+	if bs.Contains(b) {
+		return
+	}
+	bs.Add(b)
+	r.fwd[a] = bs
+	as := r.inv[b]
+	as.Add(a)
+	r.inv[b] = as
+	r.n++
+}
+
+// Remove removes the pair (a, b) if it is present.
+func (r *Relation[A, B]) Remove(a A, b B) {
+	bs, ok := r.fwd[a]
+	if !ok || !bs.Contains(b) {
+		return
+	}
+	bs.Remove(b)
+	if bs.Len() == 0 {
+		delete(r.fwd, a)
+	}
+	as := r.inv[b]
+	as.Remove(a)
+	if as.Len() == 0 {
+		delete(r.inv, b)
+	}
+	r.n--
+}
+
+// Contains reports whether a is related to b.
+func (r *Relation[A, B]) Contains(a A, b B) bool {
+	bs := r.fwd[a]
+	return bs.Contains(b)
+}
+
+// Len returns the number of pairs in r.
+func (r *Relation[A, B]) Len() int {
+	return r.n
+}
+
+// Do calls f on every pair in r, stopping if f returns false.
+// f should not change r.
+// f will be called on pairs in an indeterminate order.
+func (r *Relation[A, B]) Do(f func(a A, b B) bool) {
+	for a, bs := range r.fwd {
+		for b := range bs.m {
+			if !f(a, b) {
+				return
+			}
+		}
+	}
+}
+
+// Pairs returns the pairs in r as a slice.
+// The pairs will be in an indeterminate order.
+func (r *Relation[A, B]) Pairs() []Pair[A, B] {
+	p := make([]Pair[A, B], 0, r.n)
+	r.Do(func(a A, b B) bool {
+		p = append(p, Pair[A, B]{a, b})
+		return true
+	})
+	return p
+}
+
+// Equal reports whether r and r2 contain the same pairs.
+func (r *Relation[A, B]) Equal(r2 Relation[A, B]) bool {
+	if r.n != r2.n || len(r.fwd) != len(r2.fwd) {
+		return false
+	}
+	for a, bs := range r.fwd {
+		bs2, ok := r2.fwd[a]
+		if !ok || !bs.Equal(bs2) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of r.
+func (r *Relation[A, B]) Clone() Relation[A, B] {
+	c := Relation[A, B]{
+		fwd: make(map[A]Set[B], len(r.fwd)),
+		inv: make(map[B]Set[A], len(r.inv)),
+		n:   r.n,
+	}
+	for a, bs := range r.fwd {
+		c.fwd[a] = bs.Clone()
+	}
+	for b, as := range r.inv {
+		c.inv[b] = as.Clone()
+	}
+	return c
+}
+
+// Image returns the set of elements that the elements of s are related to.
+func (r *Relation[A, B]) Image(s Interface[A]) Set[B] {
+	return image(r.fwd, s)
+}
+
+// PreImage returns the set of elements that are related to an element of s.
+func (r *Relation[A, B]) PreImage(s Interface[B]) Set[A] {
+	return image(r.inv, s)
+}
+
+func image[X, Y comparable](idx map[X]Set[Y], s Interface[X]) Set[Y] {
+	r := Set[Y]{}
+	s.Do(func(x X) bool {
+		if ys, ok := idx[x]; ok {
+			r.AddSet(&ys)
+		}
+		return true
+	})
+	return r
+}
+
+// Inverse returns the relation with every pair of r reversed.
+func (r *Relation[A, B]) Inverse() Relation[B, A] {
+	c := r.Clone()
+	return Relation[B, A]{fwd: c.inv, inv: c.fwd, n: c.n}
+}
+
+// Domain returns the set of elements that are related to something.
+func (r *Relation[A, B]) Domain() Set[A] {
+	return keys(r.fwd)
+}
+
+// Range returns the set of elements that something is related to.
+func (r *Relation[A, B]) Range() Set[B] {
+	return keys(r.inv)
+}
+
+func keys[X, Y comparable](idx map[X]Set[Y]) Set[X] {
+	r := WithCap[X](len(idx))
+	for x := range idx {
+		r.m[x] = struct{}{}
+	}
+	return r
+}
+
+// Restrict returns the pairs of r whose first element is in s.
+func (r *Relation[A, B]) Restrict(s Interface[A]) Relation[A, B] {
+	var c Relation[A, B]
+	s.Do(func(a A) bool {
+		for b := range r.fwd[a].m {
+			c.Add(a, b)
+		}
+		return true
+	})
+	return c
+}
+
+// RestrictRange returns the pairs of r whose second element is in s.
+func (r *Relation[A, B]) RestrictRange(s Interface[B]) Relation[A, B] {
+	var c Relation[A, B]
+	s.Do(func(b B) bool {
+		for a := range r.inv[b].m {
+			c.Add(a, b)
+		}
+		return true
+	})
+	return c
+}
+
+// Compose returns the relation that relates a to c whenever r relates a
+// to some b that s relates to c.
+func Compose[A, B, C comparable](r *Relation[A, B], s *Relation[B, C]) Relation[A, C] {
+	var c Relation[A, C]
+	for a, bs := range r.fwd {
+		cs := image(s.fwd, &bs)
+		if cs.Len() == 0 {
+			continue
+		}
+		c.addAll(a, cs)
+	}
+	return c
+}
+
+// addAll relates a to every element of bs, taking ownership of bs.
+// a must not be in the domain of r.
+func (r *Relation[A, B]) addAll(a A, bs Set[B]) {
+	if r.fwd == nil {
+		r.fwd = map[A]Set[B]{}
+		r.inv = map[B]Set[A]{}
+	}
+	r.fwd[a] = bs
+	for b := range bs.m {
+		as := r.inv[b]
+		as.Add(a)
+		r.inv[b] = as
+	}
+	r.n += bs.Len()
+}
+
+// TransitiveClosure returns the smallest transitive relation containing r:
+// it relates a to b whenever b can be reached from a by following
+// one or more pairs of r. It takes O(n·p) time for a relation with n
+// elements in its domain and p pairs.
+func TransitiveClosure[A comparable](r *Relation[A, A]) Relation[A, A] {
+	var c Relation[A, A]
+	for a := range r.fwd {
+		reach := Set[A]{}
+		stack := []A{a}
+		for len(stack) > 0 {
+			x := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for y := range r.fwd[x].m {
+				if !reach.Contains(y) {
+					reach.Add(y)
+					stack = append(stack, y)
+				}
+			}
+		}
+		c.addAll(a, reach)
+	}
+	return c
+}
+
+// ReflexiveClosure returns r with every element of its domain and range
+// related to itself.
+func ReflexiveClosure[A comparable](r *Relation[A, A]) Relation[A, A] {
+	c := r.Clone()
+	for a := range r.fwd {
+		c.Add(a, a)
+	}
+	for a := range r.inv {
+		c.Add(a, a)
+	}
+	return c
+}
@@ -0,0 +1,190 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+type strPair = Pair[string, string]
+
+func TestRelation(t *testing.T) {
+	t.Parallel()
+
+	r := RelationOf(
+		strPair{"alice", "admin"},
+		strPair{"alice", "dev"},
+		strPair{"bob", "dev"},
+		strPair{"carol", "ops"},
+		strPair{"bob", "dev"},
+	)
+	r.Remove("carol", "ops")
+	r.Remove("carol", "dev")
+
+	if r.Len() != 3 {
+		t.Fatalf("len: got %v, want %v", r.Len(), 3)
+	}
+	if !r.Contains("alice", "admin") || r.Contains("carol", "ops") || r.Contains("bob", "admin") {
+		t.Fatalf("got %v, want alice→admin, alice→dev and bob→dev", r.Pairs())
+	}
+
+	sets := map[string]struct {
+		got  Set[string]
+		want Set[string]
+	}{
+		"domain":   {got: r.Domain(), want: Of("alice", "bob")},
+		"range":    {got: r.Range(), want: Of("admin", "dev")},
+		"image":    {got: r.Image(&Set[string]{m: map[string]struct{}{"bob": {}, "dave": {}}}), want: Of("dev")},
+		"preimage": {got: r.PreImage(&Set[string]{m: map[string]struct{}{"dev": {}}}), want: Of("alice", "bob")},
+	}
+	for name, tt := range sets {
+		if !tt.got.Equal(tt.want) {
+			t.Fatalf("%s: got %v, want %v", name, tt.got, tt.want)
+		}
+	}
+
+	inv := r.Inverse()
+	want := RelationOf(
+		strPair{"admin", "alice"},
+		strPair{"dev", "alice"},
+		strPair{"dev", "bob"},
+	)
+	if !inv.Equal(want) {
+		t.Fatalf("inverse: got %v, want %v", inv.Pairs(), want.Pairs())
+	}
+	inv.Add("ops", "carol")
+	if r.Contains("carol", "ops") {
+		t.Fatalf("inverse shares storage with the relation")
+	}
+}
+
+func TestRelationOperations(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		got  func() Relation[string, string]
+		want Relation[string, string]
+	}{
+		"compose": {
+			got: func() Relation[string, string] {
+				groups := RelationOf(strPair{"alice", "admin"}, strPair{"alice", "dev"}, strPair{"bob", "dev"}, strPair{"carol", "guest"})
+				perms := RelationOf(strPair{"admin", "write"}, strPair{"dev", "read"}, strPair{"admin", "read"})
+				return Compose(&groups, &perms)
+			},
+			want: RelationOf(strPair{"alice", "write"}, strPair{"alice", "read"}, strPair{"bob", "read"}),
+		},
+		"restrict": {
+			got: func() Relation[string, string] {
+				r := RelationOf(strPair{"a", "x"}, strPair{"b", "y"}, strPair{"c", "x"})
+				s := Of("a", "b", "d")
+				return r.Restrict(&s)
+			},
+			want: RelationOf(strPair{"a", "x"}, strPair{"b", "y"}),
+		},
+		"restrict range": {
+			got: func() Relation[string, string] {
+				r := RelationOf(strPair{"a", "x"}, strPair{"b", "y"}, strPair{"c", "x"})
+				s := Of("x")
+				return r.RestrictRange(&s)
+			},
+			want: RelationOf(strPair{"a", "x"}, strPair{"c", "x"}),
+		},
+		"transitive closure": {
+			got: func() Relation[string, string] {
+				r := RelationOf(strPair{"a", "b"}, strPair{"b", "c"}, strPair{"c", "d"}, strPair{"x", "y"})
+				return TransitiveClosure(&r)
+			},
+			want: RelationOf(
+				strPair{"a", "b"}, strPair{"a", "c"}, strPair{"a", "d"},
+				strPair{"b", "c"}, strPair{"b", "d"},
+				strPair{"c", "d"},
+				strPair{"x", "y"},
+			),
+		},
+		"transitive closure with cycle": {
+			got: func() Relation[string, string] {
+				r := RelationOf(strPair{"a", "b"}, strPair{"b", "a"})
+				return TransitiveClosure(&r)
+			},
+			want: RelationOf(strPair{"a", "a"}, strPair{"a", "b"}, strPair{"b", "a"}, strPair{"b", "b"}),
+		},
+		"reflexive closure": {
+			got: func() Relation[string, string] {
+				r := RelationOf(strPair{"a", "b"}, strPair{"b", "c"})
+				return ReflexiveClosure(&r)
+			},
+			want: RelationOf(
+				strPair{"a", "a"}, strPair{"b", "b"}, strPair{"c", "c"},
+				strPair{"a", "b"}, strPair{"b", "c"},
+			),
+		},
+		"empty": {
+			got: func() Relation[string, string] {
+				var r Relation[string, string]
+				c := TransitiveClosure(&r)
+				return Compose(&c, &r)
+			},
+			want: Relation[string, string]{},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.got()
+			if !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got.Pairs(), tt.want.Pairs())
+			}
+			inv := got.Inverse()
+			wantInv := tt.want.Inverse()
+			if !inv.Equal(wantInv) {
+				t.Fatalf("inverse index: got %v, want %v", inv.Pairs(), wantInv.Pairs())
+			}
+		})
+	}
+}
+
+func TestRelationAddWithNaN(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "element in set has to be equal to itself" {
+			t.Fatalf("got %v, want %v", r, "element in set has to be equal to itself")
+		}
+	}()
+	var r Relation[int, float64]
+	r.Add(1, math.NaN())
+}
+
+func BenchmarkRelation(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		var users, groups Relation[int, int]
+		for i := 0; i < n; i++ {
+			users.Add(i, i%100)
+			users.Add(i, (i*7)%100)
+			groups.Add(i%100, i)
+		}
+		chain := Relation[int, int]{}
+		for i := 0; i < n/10; i++ {
+			chain.Add(i, i+1)
+		}
+		all := users.Domain()
+		b.Run(fmt.Sprintf("Image/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				users.Image(&all)
+			}
+		})
+		b.Run(fmt.Sprintf("Compose/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Compose(&users, &groups)
+			}
+		})
+		b.Run(fmt.Sprintf("TransitiveClosure/n=%d", n/10), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				TransitiveClosure(&chain)
+			}
+		})
+	}
+}
@@ -1,19 +1,32 @@
 package set
 
-import "sync"
+// Interface is the read-only view of a set shared by every set
+// implementation. Functions that take an Interface work with any of them,
+// and take a fast path when the arguments are Sets or *Sets.
+type Interface[Elem comparable] interface {
+	// Contains reports whether v is in the set.
+	Contains(v Elem) bool
+	// Len returns the number of elements in the set.
+	Len() int
+	// Do calls f on every element in the set, stopping if f returns false.
+	Do(f func(Elem) bool)
+}
 
 type Set[Elem comparable] struct {
-	// contains filtered or unexported fields
-	initOnce sync.Once
-	m        map[Elem]struct{}
+	// m is allocated on first use, so the zero value is an empty set.
+	m map[Elem]struct{}
+	// peak is the most elements m has held or been sized for.
+	// It is brought up to date by notePeak before elements are removed,
+	// since m never shrinks.
+	peak int
 }
 
 // Of returns a new set containing the listed elements.
 func Of[Elem comparable](v ...Elem) Set[Elem] {
 	s := Set[Elem]{
-		m: make(map[Elem]struct{}, len(v)),
+		m:    make(map[Elem]struct{}, len(v)),
+		peak: len(v),
 	}
-	s.initOnce.Do(func() {})
 	for _, v := range v {
 		if v != v {
 			panic("element in set has to be equal to itself")
@@ -26,15 +39,15 @@ func Of[Elem comparable](v ...Elem) Set[Elem] {
 // WithCap returns a new set with the given capacity.
 func WithCap[Elem comparable](cap int) Set[Elem] {
 	s := Set[Elem]{
-		m: make(map[Elem]struct{}, cap),
+		m:    make(map[Elem]struct{}, cap),
+		peak: cap,
 	}
-	s.initOnce.Do(func() {})
 	return s
 }
 
 // Add adds elements to a set.
 func (s *Set[Elem]) Add(v ...Elem) {
-	s.initOnce.Do(s.init)
+	s.init()
 	for _, v := range v {
 		if v != v {
 			panic("element in set has to be equal to itself")
@@ -44,17 +57,24 @@ func (s *Set[Elem]) Add(v ...Elem) {
 }
 
 // AddSet adds the elements of set s2 to s.
-func (s *Set[Elem]) AddSet(s2 Set[Elem]) {
-	s.initOnce.Do(s.init)
-	for k := range s2.m {
-		s.m[k] = struct{}{}
+func (s *Set[Elem]) AddSet(s2 Interface[Elem]) {
+	s.init()
+	if m, ok := mapOf(s2); ok {
+		for k := range m {
+			s.m[k] = struct{}{}
+		}
+		return
 	}
+	s2.Do(func(v Elem) bool {
+		s.m[v] = struct{}{}
+		return true
+	})
 }
 
 // Remove removes elements from a set.
 // Elements that are not present are ignored.
 func (s *Set[Elem]) Remove(v ...Elem) {
-	// TODO: is initialization needed?
+	s.notePeak()
 	for _, v := range v {
 		delete(s.m, v)
 	}
@@ -62,36 +82,47 @@ func (s *Set[Elem]) Remove(v ...Elem) {
 
 // RemoveSet removes the elements of set s2 from s.
 // Elements present in s2 but not s are ignored.
-func (s *Set[Elem]) RemoveSet(s2 Set[Elem]) {
-	for k := range s2.m {
-		delete(s.m, k)
+func (s *Set[Elem]) RemoveSet(s2 Interface[Elem]) {
+	s.notePeak()
+	if m, ok := mapOf(s2); ok {
+		for k := range m {
+			delete(s.m, k)
+		}
+		return
 	}
+	s2.Do(func(v Elem) bool {
+		delete(s.m, v)
+		return true
+	})
 }
 
 // Contains reports whether v is in the set.
-func (s *Set[Elem]) Contains(v Elem) bool {
+func (s Set[Elem]) Contains(v Elem) bool {
 	_, ok := s.m[v]
 	return ok
 }
 
 // ContainsAny reports whether any of the elements in s2 are in s.
-func (s *Set[Elem]) ContainsAny(s2 Set[Elem]) bool {
-	for k := range s2.m {
-		if s.Contains(k) {
-			return true
-		}
-	}
-	return false
+func (s *Set[Elem]) ContainsAny(s2 Interface[Elem]) bool {
+	found := false
+	s2.Do(func(v Elem) bool {
+		found = s.Contains(v)
+		return !found
+	})
+	return found
 }
 
 // ContainsAll reports whether all of the elements in s2 are in s.
-func (s *Set[Elem]) ContainsAll(s2 Set[Elem]) bool {
-	for k := range s2.m {
-		if !s.Contains(k) {
-			return false
-		}
+func (s *Set[Elem]) ContainsAll(s2 Interface[Elem]) bool {
+	if s2.Len() > s.Len() {
+		return false
 	}
-	return true
+	all := true
+	s2.Do(func(v Elem) bool {
+		all = s.Contains(v)
+		return all
+	})
+	return all
 }
 
 // ToSlice returns the elements in the set s as a slice.
@@ -118,8 +149,10 @@ func (s *Set[Elem]) Equal(s2 Set[Elem]) bool {
 }
 
 // Clear removes all elements from s, leaving it empty.
+// The memory used by s is kept for reuse; see Reset to release it.
 func (s *Set[Elem]) Clear() {
-	s.m = map[Elem]struct{}{}
+	s.notePeak()
+	clear(s.m)
 }
 
 // Clone returns a copy of s.
@@ -135,6 +168,7 @@ func (s *Set[Elem]) Clone() Set[Elem] {
 
 // Retain deletes any elements from s for which keep returns false.
 func (s *Set[Elem]) Retain(keep func(Elem) bool) {
+	s.notePeak()
 	for k := range s.m {
 		if !keep(k) {
 			delete(s.m, k)
@@ -143,7 +177,7 @@ func (s *Set[Elem]) Retain(keep func(Elem) bool) {
 }
 
 // Len returns the number of elements in s.
-func (s *Set[Elem]) Len() int {
+func (s Set[Elem]) Len() int {
 	return len(s.m)
 }
 
@@ -151,7 +185,7 @@ func (s *Set[Elem]) Len() int {
 // stopping if f returns false.
 // f should not change s.
 // f will be called on values in an indeterminate order.
-func (s *Set[Elem]) Do(f func(Elem) bool) {
+func (s Set[Elem]) Do(f func(Elem) bool) {
 	for k := range s.m {
 		if !f(k) {
 			break
@@ -161,6 +195,7 @@ func (s *Set[Elem]) Do(f func(Elem) bool) {
 
 // Pop removes and returns an arbitrary element from s.
 func (s *Set[Elem]) Pop() (Elem, bool) {
+	s.notePeak()
 	for k := range s.m {
 		delete(s.m, k)
 		return k, true
@@ -169,43 +204,75 @@ func (s *Set[Elem]) Pop() (Elem, bool) {
 	return r, false
 }
 
+// mapOf returns the map of s if it is a Set or *Set.
+func mapOf[Elem comparable](s Interface[Elem]) (map[Elem]struct{}, bool) {
+	switch s := s.(type) {
+	case Set[Elem]:
+		return s.m, true
+	case *Set[Elem]:
+		return s.m, true
+	}
+	return nil, false
+}
+
+// init allocates the map of a zero set.
 func (s *Set[Elem]) init() {
-	s.m = map[Elem]struct{}{}
+	if s.m == nil {
+		s.m = map[Elem]struct{}{}
+	}
 }
 
 // Union constructs a new set containing the union of s1 and s2.
-func Union[Elem comparable](s1, s2 Set[Elem]) Set[Elem] {
-	r := WithCap[Elem](len(s1.m) + len(s2.m))
-	for k := range s1.m {
-		r.m[k] = struct{}{}
-	}
-	for k := range s2.m {
-		r.m[k] = struct{}{}
-	}
+func Union[Elem comparable](s1, s2 Interface[Elem]) Set[Elem] {
+	r := WithCap[Elem](s1.Len() + s2.Len())
+	r.AddSet(s1)
+	r.AddSet(s2)
 	return r
 }
 
 // Intersect constructs a new set containing the intersection of s1 and s2.
-func Intersect[Elem comparable](s1, s2 Set[Elem]) Set[Elem] {
-	if len(s1.m) > len(s2.m) {
+func Intersect[Elem comparable](s1, s2 Interface[Elem]) Set[Elem] {
+	if s1.Len() > s2.Len() {
 		s1, s2 = s2, s1
 	}
-	r := WithCap[Elem](len(s1.m))
-	for k := range s1.m {
-		if s2.Contains(k) {
-			r.m[k] = struct{}{}
+	r := WithCap[Elem](s1.Len())
+	if a, ok := mapOf(s1); ok {
+		if b, ok := mapOf(s2); ok {
+			for k := range a {
+				if _, ok := b[k]; ok {
+					r.m[k] = struct{}{}
+				}
+			}
+			return r
 		}
 	}
+	s1.Do(func(v Elem) bool {
+		if s2.Contains(v) {
+			r.m[v] = struct{}{}
+		}
+		return true
+	})
 	return r
 }
 
 // Difference constructs a new set containing the elements of s1 that are not in s2.
-func Difference[Elem comparable](s1, s2 Set[Elem]) Set[Elem] {
-	r := WithCap[Elem](len(s1.m))
-	for k := range s1.m {
-		if _, ok := s2.m[k]; !ok {
-			r.m[k] = struct{}{}
+func Difference[Elem comparable](s1, s2 Interface[Elem]) Set[Elem] {
+	r := WithCap[Elem](s1.Len())
+	if a, ok := mapOf(s1); ok {
+		if b, ok := mapOf(s2); ok {
+			for k := range a {
+				if _, ok := b[k]; !ok {
+					r.m[k] = struct{}{}
+				}
+			}
+			return r
 		}
 	}
+	s1.Do(func(v Elem) bool {
+		if !s2.Contains(v) {
+			r.m[v] = struct{}{}
+		}
+		return true
+	})
 	return r
 }
@@ -0,0 +1,306 @@
+// Package setcbor encodes sets as CBOR, using github.com/fxamacker/cbor/v2.
+//
+// A set is encoded as an array of its elements, tagged with tag 258, the
+// registered tag for sets. Integer sets are instead packed into an
+// RFC 8746 typed array of the element type's width, holding the values
+// in increasing order. Decoding accepts arrays and typed arrays with or
+// without tag 258, including typed arrays of other widths.
+//
+// Set and IntSet wrap set.Set so that it can be used as a field of types
+// encoded with cbor.Marshal.
+package setcbor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"unsafe"
+
+	"github.com/fxamacker/cbor/v2"
+	set "github.com/tacomeet/go-set"
+	"github.com/tacomeet/go-set/internal/wire"
+	"golang.org/x/exp/constraints"
+)
+
+// CBOR major types and tags used by this package.
+const (
+	majorBytes = 2
+	majorArray = 4
+	majorTag   = 6
+
+	tagSet = 258
+	// Typed array tags are 0b010_f_s_e_ll: f is 0 for integers, s is 1
+	// for signed types, e is 1 for little endian, and the elements are
+	// 1<<ll bytes long.
+	tagTypedFirst = 64
+	tagTypedLast  = 79
+	tagSigned     = 1 << 3
+	tagLittle     = 1 << 2
+	// tagReserved would be a little-endian sint8 array.
+	tagReserved = 76
+)
+
+// Options configures decoding. Its Strict field makes decoding fail with
+// set.ErrDuplicate if an element appears more than once.
+type Options = wire.Options
+
+// Marshal encodes s as a tagged array, with the elements in unspecified
+// order.
+func Marshal[Elem comparable](s set.Interface[Elem]) ([]byte, error) {
+	b := appendHead(nil, majorTag, tagSet)
+	b = appendHead(b, majorArray, uint64(s.Len()))
+	var err error
+	s.Do(func(v Elem) bool {
+		var e []byte
+		e, err = cbor.Marshal(v)
+		b = append(b, e...)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Unmarshal decodes a set encoded by Marshal. A null decodes as an empty
+// set.
+func Unmarshal[Elem comparable](b []byte, opts Options) (set.Set[Elem], error) {
+	b = skipSetTag(b)
+	if len(b) == 1 && b[0] == 0xf6 {
+		return set.Set[Elem]{}, nil
+	}
+	major, n, hn, ok := head(b)
+	if !ok || major != majorArray {
+		return set.Set[Elem]{}, errors.New("setcbor: not an array")
+	}
+	b = b[hn:]
+	// An indefinite-length array ends with a break byte instead.
+	indefinite := n == indefiniteLen
+	s := set.WithCap[Elem](int(min(n, uint64(len(b)))))
+	for i := uint64(0); indefinite || i < n; i++ {
+		if indefinite && len(b) > 0 && b[0] == 0xff {
+			b = b[1:]
+			break
+		}
+		var v Elem
+		rest, err := cbor.UnmarshalFirst(b, &v)
+		if err != nil {
+			return set.Set[Elem]{}, err
+		}
+		if err := wire.Check(&s, v, opts); err != nil {
+			return set.Set[Elem]{}, err
+		}
+		s.Add(v)
+		b = rest
+	}
+	if len(b) > 0 {
+		return set.Set[Elem]{}, errors.New("setcbor: trailing data after set")
+	}
+	return s, nil
+}
+
+// MarshalInts encodes s as a typed array.
+func MarshalInts[Elem constraints.Integer](s set.Interface[Elem]) ([]byte, error) {
+	v := make([]Elem, 0, s.Len())
+	s.Do(func(x Elem) bool {
+		v = append(v, x)
+		return true
+	})
+	slices.Sort(v)
+
+	size := int(unsafe.Sizeof(Elem(0)))
+	tag := uint64(tagTypedFirst)
+	if wire.IsSigned[Elem]() {
+		tag |= tagSigned
+	}
+	if size > 1 {
+		tag |= tagLittle
+	}
+	switch size {
+	case 2:
+		tag |= 1
+	case 4:
+		tag |= 2
+	case 8:
+		tag |= 3
+	}
+	b := appendHead(nil, majorTag, tag)
+	b = appendHead(b, majorBytes, uint64(len(v)*size))
+	for _, x := range v {
+		switch size {
+		case 1:
+			b = append(b, byte(x))
+		case 2:
+			b = binary.LittleEndian.AppendUint16(b, uint16(x))
+		case 4:
+			b = binary.LittleEndian.AppendUint32(b, uint32(x))
+		default:
+			b = binary.LittleEndian.AppendUint64(b, uint64(x))
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalInts decodes a set encoded by MarshalInts or Marshal.
+// It reports an error for values that don't fit in Elem.
+func UnmarshalInts[Elem constraints.Integer](b []byte, opts Options) (set.Set[Elem], error) {
+	b = skipSetTag(b)
+	major, tag, hn, ok := head(b)
+	if !ok || major != majorTag {
+		return Unmarshal[Elem](b, opts)
+	}
+	if tag < tagTypedFirst || tag > tagTypedLast || tag == tagReserved {
+		return set.Set[Elem]{}, fmt.Errorf("setcbor: unexpected tag %d", tag)
+	}
+	var data []byte
+	rest, err := cbor.UnmarshalFirst(b[hn:], &data)
+	if err != nil {
+		return set.Set[Elem]{}, err
+	}
+	if len(rest) > 0 {
+		return set.Set[Elem]{}, errors.New("setcbor: trailing data after set")
+	}
+	size := 1 << (tag & 3)
+	if len(data)%size != 0 {
+		return set.Set[Elem]{}, errors.New("setcbor: typed array length is not a multiple of its element size")
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if tag&tagLittle != 0 {
+		order = binary.LittleEndian
+	}
+	signed := tag&tagSigned != 0
+	s := set.WithCap[Elem](len(data) / size)
+	for ; len(data) > 0; data = data[size:] {
+		var x uint64
+		switch size {
+		case 1:
+			x = uint64(data[0])
+		case 2:
+			x = uint64(order.Uint16(data))
+		case 4:
+			x = uint64(order.Uint32(data))
+		default:
+			x = order.Uint64(data)
+		}
+		var v Elem
+		if signed {
+			// Sign-extend the value from its width.
+			shift := 64 - 8*size
+			i := int64(x<<shift) >> shift
+			v = Elem(i)
+			ok = int64(v) == i && (i >= 0 || wire.IsSigned[Elem]())
+		} else {
+			v = Elem(x)
+			ok = uint64(v) == x && (v >= 0)
+		}
+		if !ok {
+			return set.Set[Elem]{}, fmt.Errorf("setcbor: value out of range of %T", v)
+		}
+		if err := wire.Check(&s, v, opts); err != nil {
+			return set.Set[Elem]{}, err
+		}
+		s.Add(v)
+	}
+	return s, nil
+}
+
+// indefiniteLen is the argument head returns for indefinite lengths.
+const indefiniteLen = ^uint64(0)
+
+// appendHead appends the head of a data item with the given major type
+// and argument.
+func appendHead(b []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(b, major|byte(arg))
+	case arg <= 0xff:
+		return append(b, major|24, byte(arg))
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64(append(b, major|27), arg)
+}
+
+// head decodes the head of a data item: its major type, its argument and
+// the length of the head. The argument is indefiniteLen for indefinite
+// lengths.
+func head(b []byte) (major byte, arg uint64, n int, ok bool) {
+	if len(b) == 0 {
+		return 0, 0, 0, false
+	}
+	major, info := b[0]>>5, b[0]&31
+	switch {
+	case info < 24:
+		return major, uint64(info), 1, true
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(b) < 1+size {
+			return 0, 0, 0, false
+		}
+		for _, c := range b[1 : 1+size] {
+			arg = arg<<8 | uint64(c)
+		}
+		return major, arg, 1 + size, true
+	case info == 31:
+		return major, indefiniteLen, 1, true
+	}
+	return 0, 0, 0, false
+}
+
+// skipSetTag returns b without a leading set tag.
+func skipSetTag(b []byte) []byte {
+	if major, tag, n, ok := head(b); ok && major == majorTag && tag == tagSet {
+		return b[n:]
+	}
+	return b
+}
+
+// Set is a set.Set that encodes as a tagged CBOR array.
+type Set[Elem comparable] struct {
+	set.Set[Elem]
+	// Strict makes decoding fail on duplicate elements.
+	Strict bool
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (s Set[Elem]) MarshalCBOR() ([]byte, error) {
+	return Marshal[Elem](&s.Set)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+// It replaces the elements of s.
+func (s *Set[Elem]) UnmarshalCBOR(b []byte) error {
+	v, err := Unmarshal[Elem](b, Options{Strict: s.Strict})
+	if err != nil {
+		return err
+	}
+	s.Set = v
+	return nil
+}
+
+// IntSet is a set.Set of integers that encodes as a typed array.
+type IntSet[Elem constraints.Integer] struct {
+	set.Set[Elem]
+	// Strict makes decoding fail on duplicate elements.
+	Strict bool
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (s IntSet[Elem]) MarshalCBOR() ([]byte, error) {
+	return MarshalInts[Elem](&s.Set)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+// It replaces the elements of s.
+func (s *IntSet[Elem]) UnmarshalCBOR(b []byte) error {
+	v, err := UnmarshalInts[Elem](b, Options{Strict: s.Strict})
+	if err != nil {
+		return err
+	}
+	s.Set = v
+	return nil
+}
@@ -0,0 +1,213 @@
+package setcbor
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	set "github.com/tacomeet/go-set"
+)
+
+type record struct {
+	Tags  Set[string]
+	IDs   IntSet[int64]
+	Ports IntSet[uint16]
+	Small IntSet[int8]
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]record{
+		"empty": {},
+		"values": {
+			Tags:  Set[string]{Set: set.Of("a", "", "日本語")},
+			IDs:   IntSet[int64]{Set: set.Of[int64](-1, 0, 1, math.MinInt64, math.MaxInt64)},
+			Ports: IntSet[uint16]{Set: set.Of[uint16](80, 443, math.MaxUint16)},
+			Small: IntSet[int8]{Set: set.Of[int8](math.MinInt8, 0, math.MaxInt8)},
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			b, err := cbor.Marshal(tt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := record{
+				Tags:  Set[string]{Strict: true},
+				IDs:   IntSet[int64]{Strict: true},
+				Ports: IntSet[uint16]{Strict: true},
+				Small: IntSet[int8]{Strict: true},
+			}
+			if err := cbor.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Tags.Equal(tt.Tags.Set) {
+				t.Fatalf("got %v, want %v", got.Tags.Set, tt.Tags.Set)
+			}
+			if !got.IDs.Equal(tt.IDs.Set) {
+				t.Fatalf("got %v, want %v", got.IDs.Set, tt.IDs.Set)
+			}
+			if !got.Ports.Equal(tt.Ports.Set) {
+				t.Fatalf("got %v, want %v", got.Ports.Set, tt.Ports.Set)
+			}
+			if !got.Small.Equal(tt.Small.Set) {
+				t.Fatalf("got %v, want %v", got.Small.Set, tt.Small.Set)
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	s := set.Of("x")
+	b, err := Marshal[string](&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tag 258, an array of one element, and "x".
+	if want := []byte{0xd9, 0x01, 0x02, 0x81, 0x61, 'x'}; !bytes.Equal(b, want) {
+		t.Fatalf("got % x, want % x", b, want)
+	}
+	// Decoders that don't know the tag still see an array.
+	var v []string
+	if err := cbor.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"x"}; !slices.Equal(v, want) {
+		t.Fatalf("got %v, want %v", v, want)
+	}
+}
+
+func TestMarshalInts(t *testing.T) {
+	t.Parallel()
+
+	s := set.Of[uint16](0x0302, 0x0100)
+	b, err := MarshalInts[uint16](&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tag 69, little-endian uint16, and a 4 byte string of sorted values.
+	if want := []byte{0xd8, 69, 0x44, 0x00, 0x01, 0x02, 0x03}; !bytes.Equal(b, want) {
+		t.Fatalf("got % x, want % x", b, want)
+	}
+}
+
+func TestUnmarshalInts(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		b    []byte
+		want set.Set[int32]
+	}{
+		"big-endian uint16": {
+			b:    []byte{0xd8, 65, 0x44, 0x01, 0x00, 0xff, 0xff},
+			want: set.Of[int32](0x100, 0xffff),
+		},
+		"sint8": {
+			b:    []byte{0xd8, 72, 0x42, 0xff, 0x05},
+			want: set.Of[int32](-1, 5),
+		},
+		"set tag": {
+			b:    []byte{0xd9, 0x01, 0x02, 0xd8, 64, 0x41, 0x07},
+			want: set.Of[int32](7),
+		},
+		"array": {
+			b:    []byte{0x82, 0x20, 0x01},
+			want: set.Of[int32](-1, 1),
+		},
+		"indefinite array": {
+			b:    []byte{0x9f, 0x01, 0x02, 0xff},
+			want: set.Of[int32](1, 2),
+		},
+		"null": {
+			b:    []byte{0xf6},
+			want: set.Of[int32](),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := UnmarshalInts[int32](tt.b, Options{Strict: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string][]byte{
+		"negative into unsigned": {0xd8, 72, 0x41, 0xff},
+		"too wide":               {0xd8, 69, 0x42, 0x00, 0x01},
+		"partial element":        {0xd8, 69, 0x43, 0x01, 0x00, 0x02},
+		"reserved tag":           {0xd8, 76, 0x41, 0x01},
+		"other tag":              {0xc1, 0x01},
+		"array overflow":         {0x81, 0x19, 0x01, 0x00},
+		"not an array":           {0x01},
+		"truncated":              {0x82, 0x01},
+		"trailing data":          {0x81, 0x01, 0x01},
+	}
+	for name, b := range tests {
+		b := b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if s, err := UnmarshalInts[uint8](b, Options{}); err == nil {
+				t.Fatalf("got %v, want an error", s)
+			}
+		})
+	}
+
+	// An array holding a half-precision NaN.
+	if _, err := Unmarshal[float64]([]byte{0x81, 0xf9, 0x7e, 0x00}, Options{}); !errors.Is(err, set.ErrInvalidEncoding) {
+		t.Fatalf("NaN: got %v, want %v", err, set.ErrInvalidEncoding)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		b   []byte
+		dec func([]byte, Options) error
+	}{
+		"array": {
+			b: []byte{0x83, 0x61, 'a', 0x61, 'b', 0x61, 'a'},
+			dec: func(b []byte, opts Options) error {
+				_, err := Unmarshal[string](b, opts)
+				return err
+			},
+		},
+		"typed array": {
+			b: []byte{0xd8, 64, 0x42, 0x05, 0x05},
+			dec: func(b []byte, opts Options) error {
+				_, err := UnmarshalInts[int](b, opts)
+				return err
+			},
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.dec(tt.b, Options{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.dec(tt.b, Options{Strict: true}); !errors.Is(err, set.ErrDuplicate) {
+				t.Fatalf("got %v, want %v", err, set.ErrDuplicate)
+			}
+		})
+	}
+}
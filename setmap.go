@@ -0,0 +1,176 @@
+package set
+
+// SetMap is a multi-valued map that associates each key with a set of
+// values. A key is present only while its set is not empty: adding a
+// value creates the set and removing the last value deletes it.
+// The zero value is an empty map.
+type SetMap[K, V comparable] struct {
+	m map[K]Set[V]
+	n int
+}
+
+// Add adds the values v to the set of k.
+func (m *SetMap[K, V]) Add(k K, v ...V) {
+	if len(v) == 0 {
+		return
+	}
+	m.update(k, func(s *Set[V]) { s.Add(v...) })
+}
+
+// AddSet adds the values in s2 to the set of k.
+func (m *SetMap[K, V]) AddSet(k K, s2 Interface[V]) {
+	if s2.Len() == 0 {
+		return
+	}
+	m.update(k, func(s *Set[V]) { s.AddSet(s2) })
+}
+
+// update applies f to the set of k, creating it if needed, and keeps
+// the pair count up to date.
+func (m *SetMap[K, V]) update(k K, f func(s *Set[V])) {
+	if k != k {
+		panic("element in set has to be equal to itself")
+	}
+	if m.m == nil {
+		m.m = map[K]Set[V]{}
+	}
+	s := m.m[k]
+	n := s.Len()
+	f(&s)
+	m.n += s.Len() - n
+	if s.Len() == 0 {
+		delete(m.m, k)
+		return
+	}
+	m.m[k] = s
+}
+
+// Remove removes the values v from the set of k, removing k if its set
+// becomes empty. Values that are not present are ignored.
+func (m *SetMap[K, V]) Remove(k K, v ...V) {
+	if _, ok := m.m[k]; ok {
+		m.update(k, func(s *Set[V]) { s.Remove(v...) })
+	}
+}
+
+// RemoveSet removes the values in s2 from the set of k, removing k if
+// its set becomes empty.
+func (m *SetMap[K, V]) RemoveSet(k K, s2 Interface[V]) {
+	if _, ok := m.m[k]; ok {
+		m.update(k, func(s *Set[V]) { s.RemoveSet(s2) })
+	}
+}
+
+// RemoveKey removes k and its set.
+func (m *SetMap[K, V]) RemoveKey(k K) {
+	s := m.m[k]
+	m.n -= s.Len()
+	delete(m.m, k)
+}
+
+// Get returns a copy of the set of k, which is empty if k is not present.
+func (m *SetMap[K, V]) Get(k K) Set[V] {
+	s := m.m[k]
+	return s.Clone()
+}
+
+// Contains reports whether v is in the set of k.
+func (m *SetMap[K, V]) Contains(k K, v V) bool {
+	s := m.m[k]
+	return s.Contains(v)
+}
+
+// ContainsKey reports whether k has a set.
+func (m *SetMap[K, V]) ContainsKey(k K) bool {
+	_, ok := m.m[k]
+	return ok
+}
+
+// Keys returns the set of keys in m.
+func (m *SetMap[K, V]) Keys() Set[K] {
+	return keys(m.m)
+}
+
+// Len returns the number of keys in m.
+func (m *SetMap[K, V]) Len() int {
+	return len(m.m)
+}
+
+// Size returns the number of key and value pairs in m.
+func (m *SetMap[K, V]) Size() int {
+	return m.n
+}
+
+// Do calls f on every key and value pair in m, stopping if f returns false.
+// f should not change m.
+// f will be called on pairs in an indeterminate order.
+func (m *SetMap[K, V]) Do(f func(k K, v V) bool) {
+	for k, s := range m.m {
+		for v := range s.m {
+			if !f(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// UnionValues returns the set of values associated with any key.
+func (m *SetMap[K, V]) UnionValues() Set[V] {
+	r := Set[V]{}
+	for _, s := range m.m {
+		r.AddSet(&s)
+	}
+	return r
+}
+
+// Invert returns the map that associates each value of m with the set
+// of keys whose sets contain it.
+func (m *SetMap[K, V]) Invert() SetMap[V, K] {
+	var r SetMap[V, K]
+	m.Do(func(k K, v V) bool {
+		r.Add(v, k)
+		return true
+	})
+	return r
+}
+
+// AddMap adds the values of every key of m2 to the set of that key in m.
+func (m *SetMap[K, V]) AddMap(m2 *SetMap[K, V]) {
+	for k, s := range m2.m {
+		m.AddSet(k, &s)
+	}
+}
+
+// RemoveMap removes the values of every key of m2 from the set of that
+// key in m, removing keys whose sets become empty.
+func (m *SetMap[K, V]) RemoveMap(m2 *SetMap[K, V]) {
+	for k, s := range m2.m {
+		m.RemoveSet(k, &s)
+	}
+}
+
+// Equal reports whether m and m2 associate the same keys with the same
+// sets.
+func (m *SetMap[K, V]) Equal(m2 SetMap[K, V]) bool {
+	if m.n != m2.n || len(m.m) != len(m2.m) {
+		return false
+	}
+	for k, s := range m.m {
+		s2, ok := m2.m[k]
+		if !ok || !s.Equal(s2) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of m.
+// The values are copied using assignment,
+// so this is a shallow clone.
+func (m *SetMap[K, V]) Clone() SetMap[K, V] {
+	r := SetMap[K, V]{m: make(map[K]Set[V], len(m.m)), n: m.n}
+	for k, s := range m.m {
+		r.m[k] = s.Clone()
+	}
+	return r
+}
@@ -0,0 +1,177 @@
+package set
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSetMap(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		ops      func(m *SetMap[string, int])
+		want     map[string]Set[int]
+		wantSize int
+	}{
+		"zero value": {
+			ops:  func(m *SetMap[string, int]) {},
+			want: map[string]Set[int]{},
+		},
+		"add": {
+			ops: func(m *SetMap[string, int]) {
+				m.Add("a", 1, 2)
+				m.Add("b", 2)
+				m.Add("a", 2, 3)
+				m.Add("c")
+			},
+			want:     map[string]Set[int]{"a": Of(1, 2, 3), "b": Of(2)},
+			wantSize: 4,
+		},
+		"remove deletes empty keys": {
+			ops: func(m *SetMap[string, int]) {
+				m.Add("a", 1, 2)
+				m.Add("b", 2)
+				m.Remove("a", 1, 5)
+				m.Remove("b", 2)
+				m.Remove("c", 2)
+			},
+			want:     map[string]Set[int]{"a": Of(2)},
+			wantSize: 1,
+		},
+		"remove key": {
+			ops: func(m *SetMap[string, int]) {
+				m.Add("a", 1, 2)
+				m.Add("b", 2)
+				m.RemoveKey("a")
+				m.RemoveKey("c")
+			},
+			want:     map[string]Set[int]{"b": Of(2)},
+			wantSize: 1,
+		},
+		"add set": {
+			ops: func(m *SetMap[string, int]) {
+				s := Of(1, 2)
+				m.AddSet("a", &s)
+				empty := Of[int]()
+				m.AddSet("b", &empty)
+			},
+			want:     map[string]Set[int]{"a": Of(1, 2)},
+			wantSize: 2,
+		},
+		"add map": {
+			ops: func(m *SetMap[string, int]) {
+				m.Add("a", 1)
+				var m2 SetMap[string, int]
+				m2.Add("a", 1, 2)
+				m2.Add("b", 3)
+				m.AddMap(&m2)
+			},
+			want:     map[string]Set[int]{"a": Of(1, 2), "b": Of(3)},
+			wantSize: 3,
+		},
+		"remove map": {
+			ops: func(m *SetMap[string, int]) {
+				m.Add("a", 1, 2)
+				m.Add("b", 3)
+				var m2 SetMap[string, int]
+				m2.Add("a", 1)
+				m2.Add("b", 3)
+				m2.Add("c", 4)
+				m.RemoveMap(&m2)
+			},
+			want:     map[string]Set[int]{"a": Of(2)},
+			wantSize: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var m SetMap[string, int]
+			tt.ops(&m)
+			if m.Len() != len(tt.want) {
+				t.Fatalf("len: got %v, want %v", m.Len(), len(tt.want))
+			}
+			if m.Size() != tt.wantSize {
+				t.Fatalf("size: got %v, want %v", m.Size(), tt.wantSize)
+			}
+			keys := m.Keys()
+			for k, want := range tt.want {
+				if got := m.Get(k); !got.Equal(want) {
+					t.Fatalf("get %v: got %v, want %v", k, got, want)
+				}
+				if !keys.Contains(k) || !m.ContainsKey(k) {
+					t.Fatalf("keys: got %v, want %v in them", keys, k)
+				}
+				for _, v := range want.ToSlice() {
+					if !m.Contains(k, v) {
+						t.Fatalf("contains %v %v: got false, want true", k, v)
+					}
+				}
+			}
+			if m.Contains("z", 1) || m.ContainsKey("z") {
+				t.Fatalf("contains z: got true, want false")
+			}
+		})
+	}
+}
+
+func TestSetMapGetIsCopy(t *testing.T) {
+	t.Parallel()
+
+	var m SetMap[string, int]
+	m.Add("a", 1)
+	s := m.Get("a")
+	s.Add(2)
+	if m.Contains("a", 2) {
+		t.Fatalf("Get shares storage with the map")
+	}
+	if got := m.Get("b"); got.Len() != 0 {
+		t.Fatalf("got %v, want empty set", got)
+	}
+}
+
+func TestSetMapInvert(t *testing.T) {
+	t.Parallel()
+
+	var m SetMap[string, int]
+	m.Add("a", 1, 2)
+	m.Add("b", 2, 3)
+
+	inv := m.Invert()
+	var want SetMap[int, string]
+	want.Add(1, "a")
+	want.Add(2, "a", "b")
+	want.Add(3, "b")
+	if !inv.Equal(want) {
+		t.Fatalf("got %v, want %v", inv.Keys(), want.Keys())
+	}
+	if got := m.UnionValues(); !got.Equal(Of(1, 2, 3)) {
+		t.Fatalf("union values: got %v, want %v", got, Of(1, 2, 3))
+	}
+
+	twice := inv.Invert()
+	if !twice.Equal(m) {
+		t.Fatalf("inverting twice: got %v, want %v", twice.Keys(), m.Keys())
+	}
+
+	c := m.Clone()
+	c.Add("a", 10)
+	if m.Contains("a", 10) {
+		t.Fatalf("Clone shares storage with the map")
+	}
+}
+
+func TestSetMapAddWithNaN(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "element in set has to be equal to itself" {
+			t.Fatalf("got %v, want %v", r, "element in set has to be equal to itself")
+		}
+	}()
+	var m SetMap[float64, int]
+	m.Add(math.NaN(), 1)
+}
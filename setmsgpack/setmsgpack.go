@@ -0,0 +1,254 @@
+// Package setmsgpack encodes sets as MessagePack, using
+// github.com/vmihailenco/msgpack/v5.
+//
+// A set is encoded as an array of its elements. Integer sets can instead
+// be packed into a bin holding the sorted values as varints: the first
+// value, zig-zag encoded for signed types, then the difference from each
+// value to the next. Decoding an integer set accepts either form.
+//
+// Set and IntSet wrap set.Set so that it can be used as a field of types
+// encoded with msgpack.Marshal; Encode, Decode, EncodeInts and DecodeInts
+// are for custom encoders.
+package setmsgpack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	set "github.com/tacomeet/go-set"
+	"github.com/tacomeet/go-set/internal/wire"
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+	"golang.org/x/exp/constraints"
+)
+
+// Options configures decoding. Its Strict field makes decoding fail with
+// set.ErrDuplicate if an element appears more than once.
+type Options = wire.Options
+
+// Encode writes the elements of s as an array, in unspecified order.
+func Encode[Elem comparable](enc *msgpack.Encoder, s set.Interface[Elem]) error {
+	if err := enc.EncodeArrayLen(s.Len()); err != nil {
+		return err
+	}
+	var err error
+	s.Do(func(v Elem) bool {
+		err = enc.Encode(v)
+		return err == nil
+	})
+	return err
+}
+
+// Decode reads a set written by Encode. A nil decodes as an empty set.
+func Decode[Elem comparable](dec *msgpack.Decoder, opts Options) (set.Set[Elem], error) {
+	n, err := dec.DecodeArrayLen()
+	if err != nil {
+		return set.Set[Elem]{}, err
+	}
+	s := set.WithCap[Elem](capHint(n))
+	for i := 0; i < n; i++ {
+		var v Elem
+		if err := dec.Decode(&v); err != nil {
+			return set.Set[Elem]{}, err
+		}
+		if err := wire.Check(&s, v, opts); err != nil {
+			return set.Set[Elem]{}, err
+		}
+		s.Add(v)
+	}
+	return s, nil
+}
+
+// maxCapHint bounds the capacity reserved for a decoded array, whose
+// length comes from the input and may be far more than the input holds.
+const maxCapHint = 1024
+
+func capHint(n int) int {
+	return min(max(n, 0), maxCapHint)
+}
+
+// EncodeInts writes the elements of s packed into a bin.
+func EncodeInts[Elem constraints.Integer](enc *msgpack.Encoder, s set.Interface[Elem]) error {
+	v := make([]Elem, 0, s.Len())
+	s.Do(func(x Elem) bool {
+		v = append(v, x)
+		return true
+	})
+	slices.Sort(v)
+	b := make([]byte, 0, len(v)+binary.MaxVarintLen64)
+	for i, x := range v {
+		switch {
+		case i > 0:
+			// Sorted values differ by less than 2⁶⁴, so the difference
+			// is exact in wrapping uint64 arithmetic.
+			b = binary.AppendUvarint(b, uint64(x)-uint64(v[i-1]))
+		case wire.IsSigned[Elem]():
+			b = binary.AppendVarint(b, int64(x))
+		default:
+			b = binary.AppendUvarint(b, uint64(x))
+		}
+	}
+	return enc.EncodeBytes(b)
+}
+
+var errPacked = errors.New("setmsgpack: invalid packed integers")
+
+// DecodeInts reads a set written by EncodeInts or Encode.
+// It reports an error for values that don't fit in Elem.
+func DecodeInts[Elem constraints.Integer](dec *msgpack.Decoder, opts Options) (set.Set[Elem], error) {
+	c, err := dec.PeekCode()
+	if err != nil {
+		return set.Set[Elem]{}, err
+	}
+	if !msgpcode.IsBin(c) {
+		return decodeIntArray[Elem](dec, opts)
+	}
+	n, err := dec.DecodeBytesLen()
+	if err != nil {
+		return set.Set[Elem]{}, err
+	}
+	b, err := readBytes(dec, n)
+	if err != nil {
+		return set.Set[Elem]{}, err
+	}
+	var s set.Set[Elem]
+	var prev Elem
+	for first := true; len(b) > 0; first = false {
+		var v Elem
+		var n int
+		switch {
+		case !first:
+			var d uint64
+			d, n = binary.Uvarint(b)
+			x := uint64(prev) + d
+			v = Elem(x)
+			// The sum must neither wrap nor overflow Elem.
+			if uint64(v) != x || v < prev {
+				return set.Set[Elem]{}, errPacked
+			}
+		case wire.IsSigned[Elem]():
+			var x int64
+			x, n = binary.Varint(b)
+			v = Elem(x)
+			if int64(v) != x {
+				return set.Set[Elem]{}, errPacked
+			}
+		default:
+			var x uint64
+			x, n = binary.Uvarint(b)
+			v = Elem(x)
+			if uint64(v) != x {
+				return set.Set[Elem]{}, errPacked
+			}
+		}
+		if n <= 0 {
+			return set.Set[Elem]{}, errPacked
+		}
+		if err := wire.Check(&s, v, opts); err != nil {
+			return set.Set[Elem]{}, err
+		}
+		s.Add(v)
+		b = b[n:]
+		prev = v
+	}
+	return s, nil
+}
+
+// readBytes reads the n bytes of a bin. Unlike dec.DecodeBytes, it
+// grows the buffer as the bytes arrive rather than allocating n bytes up
+// front.
+func readBytes(dec *msgpack.Decoder, n int) ([]byte, error) {
+	var b []byte
+	for len(b) < n {
+		b = slices.Grow(b, min(n-len(b), max(len(b), 512)))
+		k := min(n, cap(b))
+		if err := dec.ReadFull(b[len(b):k]); err != nil {
+			return nil, err
+		}
+		b = b[:k]
+	}
+	return b, nil
+}
+
+// decodeIntArray is Decode for integers, checking that the values fit
+// in Elem rather than truncating them.
+func decodeIntArray[Elem constraints.Integer](dec *msgpack.Decoder, opts Options) (set.Set[Elem], error) {
+	n, err := dec.DecodeArrayLen()
+	if err != nil {
+		return set.Set[Elem]{}, err
+	}
+	s := set.WithCap[Elem](capHint(n))
+	for i := 0; i < n; i++ {
+		var v Elem
+		if wire.IsSigned[Elem]() {
+			x, err := dec.DecodeInt64()
+			if err != nil {
+				return set.Set[Elem]{}, err
+			}
+			if v = Elem(x); int64(v) != x {
+				return set.Set[Elem]{}, fmt.Errorf("setmsgpack: value %d out of range", x)
+			}
+		} else {
+			x, err := dec.DecodeUint64()
+			if err != nil {
+				return set.Set[Elem]{}, err
+			}
+			if v = Elem(x); uint64(v) != x {
+				return set.Set[Elem]{}, fmt.Errorf("setmsgpack: value %d out of range", x)
+			}
+		}
+		if err := wire.Check(&s, v, opts); err != nil {
+			return set.Set[Elem]{}, err
+		}
+		s.Add(v)
+	}
+	return s, nil
+}
+
+// Set is a set.Set that encodes as a MessagePack array.
+type Set[Elem comparable] struct {
+	set.Set[Elem]
+	// Strict makes decoding fail on duplicate elements.
+	Strict bool
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (s Set[Elem]) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return Encode[Elem](enc, &s.Set)
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+// It replaces the elements of s.
+func (s *Set[Elem]) DecodeMsgpack(dec *msgpack.Decoder) error {
+	v, err := Decode[Elem](dec, Options{Strict: s.Strict})
+	if err != nil {
+		return err
+	}
+	s.Set = v
+	return nil
+}
+
+// IntSet is a set.Set of integers that encodes as packed integers.
+type IntSet[Elem constraints.Integer] struct {
+	set.Set[Elem]
+	// Strict makes decoding fail on duplicate elements.
+	Strict bool
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (s IntSet[Elem]) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return EncodeInts[Elem](enc, &s.Set)
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+// It replaces the elements of s.
+func (s *IntSet[Elem]) DecodeMsgpack(dec *msgpack.Decoder) error {
+	v, err := DecodeInts[Elem](dec, Options{Strict: s.Strict})
+	if err != nil {
+		return err
+	}
+	s.Set = v
+	return nil
+}
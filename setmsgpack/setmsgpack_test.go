@@ -0,0 +1,199 @@
+package setmsgpack
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"testing"
+
+	set "github.com/tacomeet/go-set"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type record struct {
+	Tags  Set[string]
+	IDs   IntSet[int64]
+	Small IntSet[uint8]
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]record{
+		"empty": {},
+		"values": {
+			Tags:  Set[string]{Set: set.Of("a", "", "日本語")},
+			IDs:   IntSet[int64]{Set: set.Of[int64](-1, 0, 1, math.MinInt64, math.MaxInt64)},
+			Small: IntSet[uint8]{Set: set.Of[uint8](0, 1, 255)},
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			b, err := msgpack.Marshal(tt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := record{
+				Tags:  Set[string]{Strict: true},
+				IDs:   IntSet[int64]{Strict: true},
+				Small: IntSet[uint8]{Strict: true},
+			}
+			if err := msgpack.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Tags.Equal(tt.Tags.Set) {
+				t.Fatalf("got %v, want %v", got.Tags.Set, tt.Tags.Set)
+			}
+			if !got.IDs.Equal(tt.IDs.Set) {
+				t.Fatalf("got %v, want %v", got.IDs.Set, tt.IDs.Set)
+			}
+			if !got.Small.Equal(tt.Small.Set) {
+				t.Fatalf("got %v, want %v", got.Small.Set, tt.Small.Set)
+			}
+		})
+	}
+}
+
+func TestPacked(t *testing.T) {
+	t.Parallel()
+
+	s := IntSet[uint32]{}
+	for i := uint32(0); i < 1000; i++ {
+		s.Add(1<<20 + 3*i)
+	}
+	b, err := msgpack.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A bin header, 3 bytes for the first value and 1 for each delta.
+	if want := 3 + 3 + 999; len(b) != want {
+		t.Fatalf("got %d bytes, want %d", len(b), want)
+	}
+
+	// Packed sets decode as plain arrays too.
+	var got IntSet[uint32]
+	if err := msgpack.Unmarshal(mustMarshal(t, Set[uint32]{Set: s.Set}), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(s.Set) {
+		t.Fatalf("got %v, want %v", got.Set, s.Set)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		b   []byte
+		dec func([]byte, bool) error
+	}{
+		"array": {
+			b: mustMarshal(t, []string{"a", "b", "a"}),
+			dec: func(b []byte, strict bool) error {
+				s := Set[string]{Strict: strict}
+				return msgpack.Unmarshal(b, &s)
+			},
+		},
+		"packed": {
+			// 5, then a zero delta.
+			b: mustMarshal(t, []byte{5, 0}),
+			dec: func(b []byte, strict bool) error {
+				s := IntSet[int]{Strict: strict}
+				return msgpack.Unmarshal(b, &s)
+			},
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.dec(tt.b, false); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.dec(tt.b, true); !errors.Is(err, set.ErrDuplicate) {
+				t.Fatalf("got %v, want %v", err, set.ErrDuplicate)
+			}
+		})
+	}
+}
+
+func TestDecodeIntsInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string][]byte{
+		"overflow":       mustMarshal(t, []byte{0x80, 0x02}),
+		"overflow delta": mustMarshal(t, []byte{0xfe, 0x01, 0x02}),
+		"truncated":      mustMarshal(t, []byte{0x80}),
+		"array overflow": mustMarshal(t, []int{1, 256}),
+		"wrong type":     mustMarshal(t, "a"),
+		"negative":       mustMarshal(t, []int{-1}),
+	}
+	for name, b := range tests {
+		b := b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var s IntSet[uint8]
+			if err := msgpack.Unmarshal(b, &s); err == nil {
+				t.Fatalf("got %v, want an error", s.Set)
+			}
+		})
+	}
+
+	var s Set[float64]
+	if err := msgpack.Unmarshal(mustMarshal(t, []float64{math.NaN()}), &s); !errors.Is(err, set.ErrInvalidEncoding) {
+		t.Fatalf("NaN: got %v, want %v", err, set.ErrInvalidEncoding)
+	}
+}
+
+func TestDecodeHostileLength(t *testing.T) {
+	// Not parallel, so that the allocations counted are the decoder's.
+	tests := map[string]struct {
+		b   []byte
+		dec func([]byte) error
+	}{
+		"array": {
+			b: []byte{0xdd, 0x7f, 0xff, 0xff, 0xff},
+			dec: func(b []byte) error {
+				var s Set[string]
+				return msgpack.Unmarshal(b, &s)
+			},
+		},
+		"int array": {
+			b: []byte{0xdd, 0x7f, 0xff, 0xff, 0xff},
+			dec: func(b []byte) error {
+				var s IntSet[int64]
+				return msgpack.Unmarshal(b, &s)
+			},
+		},
+		"packed": {
+			b: []byte{0xc6, 0x7f, 0xff, 0xff, 0xff},
+			dec: func(b []byte) error {
+				var s IntSet[int64]
+				return msgpack.Unmarshal(b, &s)
+			},
+		},
+	}
+	for name, tt := range tests {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		err := tt.dec(tt.b)
+		runtime.ReadMemStats(&after)
+		if err == nil {
+			t.Fatalf("%s: got nil error", name)
+		}
+		if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+			t.Fatalf("%s: got %d bytes allocated, want at most %d", name, n, 1<<20)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := msgpack.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,219 @@
+// Package setpb converts sets to and from Protocol Buffers.
+//
+// Generated code represents a repeated field as a slice. FromRepeated and
+// ToRepeated convert between such slices and sets, in the style of the
+// well-known type helpers timestamppb.New and Timestamp.AsTime.
+//
+// The Marshal and Unmarshal functions encode a set directly as a message
+// whose only field is a repeated field numbered 1, such as one of
+//
+//	message IntSet    { repeated sint64 values = 1; }
+//	message UintSet   { repeated uint64 values = 1; }
+//	message StringSet { repeated string values = 1; }
+//
+// so that the bytes can be embedded in a bytes field or decoded by any
+// protobuf implementation. Integer sets are written packed, and either
+// form is accepted when decoding.
+package setpb
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	set "github.com/tacomeet/go-set"
+	"github.com/tacomeet/go-set/internal/wire"
+	"golang.org/x/exp/constraints"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// valuesField is the number of the repeated field holding the elements.
+const valuesField protowire.Number = 1
+
+// Options configures decoding. Its Strict field makes decoding fail with
+// set.ErrDuplicate if an element appears more than once.
+type Options = wire.Options
+
+// ToRepeated returns the elements of s in increasing order, for a
+// repeated field.
+func ToRepeated[Elem cmp.Ordered](s set.Interface[Elem]) []Elem {
+	v := make([]Elem, 0, s.Len())
+	s.Do(func(x Elem) bool {
+		v = append(v, x)
+		return true
+	})
+	slices.Sort(v)
+	return v
+}
+
+// FromRepeated returns the set of the values of a repeated field.
+func FromRepeated[Elem comparable](v []Elem, opts Options) (set.Set[Elem], error) {
+	s := set.WithCap[Elem](len(v))
+	for _, x := range v {
+		if err := wire.Check(&s, x, opts); err != nil {
+			return set.Set[Elem]{}, err
+		}
+		s.Add(x)
+	}
+	return s, nil
+}
+
+// MarshalInts encodes s as an IntSet message if Elem is a signed type,
+// or as a UintSet message otherwise. The values are packed and sorted.
+func MarshalInts[Elem constraints.Integer](s set.Interface[Elem]) []byte {
+	v := ToRepeated(s)
+	signed := wire.IsSigned[Elem]()
+	n := 0
+	for _, x := range v {
+		n += protowire.SizeVarint(varint(x, signed))
+	}
+	b := make([]byte, 0, protowire.SizeTag(valuesField)+protowire.SizeBytes(n))
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, valuesField, protowire.BytesType)
+	b = protowire.AppendVarint(b, uint64(n))
+	for _, x := range v {
+		b = protowire.AppendVarint(b, varint(x, signed))
+	}
+	return b
+}
+
+// UnmarshalInts decodes a set encoded by MarshalInts.
+// It reports an error for values that don't fit in Elem.
+func UnmarshalInts[Elem constraints.Integer](b []byte, opts Options) (set.Set[Elem], error) {
+	var s set.Set[Elem]
+	signed := wire.IsSigned[Elem]()
+	addVarint := func(u uint64) error {
+		v, ok := fromVarint[Elem](u, signed)
+		if !ok {
+			return fmt.Errorf("setpb: value %d out of range", u)
+		}
+		if err := wire.Check(&s, v, opts); err != nil {
+			return err
+		}
+		s.Add(v)
+		return nil
+	}
+	err := fields(b, func(typ protowire.Type, b []byte) (int, error) {
+		switch typ {
+		case protowire.VarintType:
+			u, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return n, nil
+			}
+			return n, addVarint(u)
+		case protowire.BytesType:
+			packed, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return n, nil
+			}
+			for len(packed) > 0 {
+				u, m := protowire.ConsumeVarint(packed)
+				if m < 0 {
+					return m, nil
+				}
+				if err := addVarint(u); err != nil {
+					return 0, err
+				}
+				packed = packed[m:]
+			}
+			return n, nil
+		}
+		return 0, errWireType
+	})
+	if err != nil {
+		return set.Set[Elem]{}, err
+	}
+	return s, nil
+}
+
+// MarshalStrings encodes s as a StringSet message, with the values in
+// increasing order.
+func MarshalStrings(s set.Interface[string]) []byte {
+	var b []byte
+	for _, v := range ToRepeated(s) {
+		b = protowire.AppendTag(b, valuesField, protowire.BytesType)
+		b = protowire.AppendString(b, v)
+	}
+	return b
+}
+
+// UnmarshalStrings decodes a set encoded by MarshalStrings.
+func UnmarshalStrings(b []byte, opts Options) (set.Set[string], error) {
+	var s set.Set[string]
+	err := fields(b, func(typ protowire.Type, b []byte) (int, error) {
+		if typ != protowire.BytesType {
+			return 0, errWireType
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n, nil
+		}
+		if !utf8.Valid(v) {
+			return 0, errors.New("setpb: string is not valid UTF-8")
+		}
+		if err := wire.Check(&s, string(v), opts); err != nil {
+			return 0, err
+		}
+		s.Add(string(v))
+		return n, nil
+	})
+	if err != nil {
+		return set.Set[string]{}, err
+	}
+	return s, nil
+}
+
+var errWireType = errors.New("setpb: wrong wire type for values field")
+
+// fields calls value with the wire type and remaining bytes of each
+// occurrence of the values field in b, skipping other fields as unknown
+// fields. value returns the length of the field value, or a negative
+// protowire error code.
+func fields(b []byte, value func(typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if num == valuesField {
+			n, err := value(typ, b)
+			if err != nil {
+				return err
+			}
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// varint returns the varint of an sint64 or uint64 field holding v.
+func varint[Elem constraints.Integer](v Elem, signed bool) uint64 {
+	if signed {
+		return protowire.EncodeZigZag(int64(v))
+	}
+	return uint64(v)
+}
+
+// fromVarint is the inverse of varint. It reports false if the value
+// doesn't fit in Elem.
+func fromVarint[Elem constraints.Integer](u uint64, signed bool) (Elem, bool) {
+	if signed {
+		x := protowire.DecodeZigZag(u)
+		return Elem(x), int64(Elem(x)) == x
+	}
+	return Elem(u), uint64(Elem(u)) == u
+}
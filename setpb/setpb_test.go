@@ -0,0 +1,242 @@
+package setpb
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	set "github.com/tacomeet/go-set"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// messages returns the descriptors of the messages in the package doc.
+func messages(t *testing.T) protoreflect.MessageDescriptors {
+	t.Helper()
+	msg := func(name string, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{
+			Name: proto.String(name),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("values"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+				Type:     typ.Enum(),
+				JsonName: proto.String("values"),
+			}},
+		}
+	}
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("set.proto"),
+		Package: proto.String("setpb.test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			msg("IntSet", descriptorpb.FieldDescriptorProto_TYPE_SINT64),
+			msg("UintSet", descriptorpb.FieldDescriptorProto_TYPE_UINT64),
+			msg("StringSet", descriptorpb.FieldDescriptorProto_TYPE_STRING),
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Messages()
+}
+
+// values decodes b as the named message with the protobuf runtime and
+// returns its values field.
+func values(t *testing.T, name protoreflect.Name, b []byte) []any {
+	t.Helper()
+	md := messages(t).ByName(name)
+	m := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(b, m); err != nil {
+		t.Fatal(err)
+	}
+	list := m.Get(md.Fields().ByNumber(1)).List()
+	var r []any
+	for i := 0; i < list.Len(); i++ {
+		r = append(r, list.Get(i).Interface())
+	}
+	return r
+}
+
+// marshal encodes v as the named message with the protobuf runtime.
+func marshal(t *testing.T, name protoreflect.Name, v ...any) []byte {
+	t.Helper()
+	md := messages(t).ByName(name)
+	m := dynamicpb.NewMessage(md)
+	list := m.Mutable(md.Fields().ByNumber(1)).List()
+	for _, x := range v {
+		list.Append(protoreflect.ValueOf(x))
+	}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestRepeated(t *testing.T) {
+	t.Parallel()
+
+	s := set.Of(3, 1, 2)
+	if got, want := ToRepeated[int](&s), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	got, err := FromRepeated([]int{3, 1, 2, 1}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(s) {
+		t.Fatalf("got %v, want %v", got, s)
+	}
+	if _, err := FromRepeated([]int{3, 1, 2, 1}, Options{Strict: true}); !errors.Is(err, set.ErrDuplicate) {
+		t.Fatalf("got %v, want %v", err, set.ErrDuplicate)
+	}
+}
+
+func TestIntsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		s    set.Set[int64]
+		want []any
+	}{
+		"empty":   {set.Of[int64](), nil},
+		"single":  {set.Of[int64](0), []any{int64(0)}},
+		"extreme": {set.Of[int64](-1, 1, math.MinInt64, math.MaxInt64), []any{int64(math.MinInt64), int64(-1), int64(1), int64(math.MaxInt64)}},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			b := MarshalInts[int64](&tt.s)
+			if got := values(t, "IntSet", b); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			got, err := UnmarshalInts[int64](b, Options{Strict: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.s) {
+				t.Fatalf("got %v, want %v", got, tt.s)
+			}
+		})
+	}
+}
+
+func TestIntsPacked(t *testing.T) {
+	t.Parallel()
+
+	s := set.Of[uint8](1, 2, 3, 255)
+	b := MarshalInts[uint8](&s)
+	// One tag, one length, and the values: 255 takes two bytes.
+	if len(b) != 2+5 {
+		t.Fatalf("got %d bytes, want %d", len(b), 2+5)
+	}
+	if got, want := values(t, "UintSet", b), []any{uint64(1), uint64(2), uint64(3), uint64(255)}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestUnmarshalInts(t *testing.T) {
+	t.Parallel()
+
+	var unpacked []byte
+	for _, v := range []uint64{7, 8, 7} {
+		unpacked = protowire.AppendTag(unpacked, 1, protowire.VarintType)
+		unpacked = protowire.AppendVarint(unpacked, v)
+	}
+	unknown := protowire.AppendTag(nil, 2, protowire.BytesType)
+	unknown = protowire.AppendString(unknown, "ignored")
+
+	tests := map[string]struct {
+		b       []byte
+		strict  bool
+		want    set.Set[uint32]
+		wantErr error
+	}{
+		"runtime": {
+			b:    marshal(t, "UintSet", uint64(5), uint64(1), uint64(1)),
+			want: set.Of[uint32](1, 5),
+		},
+		"runtime strict": {
+			b:       marshal(t, "UintSet", uint64(5), uint64(1), uint64(1)),
+			strict:  true,
+			wantErr: set.ErrDuplicate,
+		},
+		"unpacked": {
+			b:    unpacked,
+			want: set.Of[uint32](7, 8),
+		},
+		"unknown field": {
+			b:    append(unknown, marshal(t, "UintSet", uint64(4))...),
+			want: set.Of[uint32](4),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := UnmarshalInts[uint32](tt.b, Options{Strict: tt.strict})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalIntsInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string][]byte{
+		"out of range": marshal(t, "UintSet", uint64(1<<32)),
+		"truncated":    marshal(t, "UintSet", uint64(1), uint64(300))[:4],
+		"wire type":    protowire.AppendFixed32(protowire.AppendTag(nil, 1, protowire.Fixed32Type), 1),
+	}
+	for name, b := range tests {
+		b := b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := UnmarshalInts[uint32](b, Options{}); err == nil {
+				t.Fatal("got nil error")
+			}
+		})
+	}
+
+	if _, err := FromRepeated([]float64{1, math.NaN()}, Options{}); !errors.Is(err, set.ErrInvalidEncoding) {
+		t.Fatalf("NaN: got %v, want %v", err, set.ErrInvalidEncoding)
+	}
+}
+
+func TestStringsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	s := set.Of("b", "", "日本語")
+	b := MarshalStrings(&s)
+	if got, want := values(t, "StringSet", b), []any{"", "b", "日本語"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	got, err := UnmarshalStrings(b, Options{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(s) {
+		t.Fatalf("got %v, want %v", got, s)
+	}
+
+	dup := marshal(t, "StringSet", "a", "a")
+	if _, err := UnmarshalStrings(dup, Options{Strict: true}); !errors.Is(err, set.ErrDuplicate) {
+		t.Fatalf("got %v, want %v", err, set.ErrDuplicate)
+	}
+	invalid := protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), []byte{0xff})
+	if _, err := UnmarshalStrings(invalid, Options{}); err == nil {
+		t.Fatal("got nil error for invalid UTF-8")
+	}
+}
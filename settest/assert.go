@@ -0,0 +1,94 @@
+package settest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	set "github.com/tacomeet/go-set"
+	"github.com/tacomeet/go-set/internal/order"
+)
+
+// AssertEqual reports an error if got and want do not contain the same
+// elements. The message lists the missing and unexpected elements
+// in sorted order. It returns whether the sets were equal.
+func AssertEqual[Elem comparable](t testing.TB, got, want set.Interface[Elem]) bool {
+	t.Helper()
+	missing, unexpected := onlyIn(want, got), onlyIn(got, want)
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return true
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "sets differ (got %d elements, want %d)", got.Len(), want.Len())
+	if len(missing) > 0 {
+		fmt.Fprintf(&b, "\n\tmissing:    %v", missing)
+	}
+	if len(unexpected) > 0 {
+		fmt.Fprintf(&b, "\n\tunexpected: %v", unexpected)
+	}
+	t.Error(b.String())
+	return false
+}
+
+// AssertContains reports an error if any of the elements v are not in s.
+// The message lists the missing elements in sorted order.
+// It returns whether all of them were present.
+func AssertContains[Elem comparable](t testing.TB, s set.Interface[Elem], v ...Elem) bool {
+	t.Helper()
+	var missing []Elem
+	for _, v := range v {
+		if !s.Contains(v) {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) == 0 {
+		return true
+	}
+	order.Sort(missing)
+	t.Errorf("set of %d elements is missing %v", s.Len(), missing)
+	return false
+}
+
+// AssertSubset reports an error if sub is not a subset of super.
+// The message lists the elements of sub that are not in super
+// in sorted order. It returns whether sub was a subset.
+func AssertSubset[Elem comparable](t testing.TB, sub, super set.Interface[Elem]) bool {
+	t.Helper()
+	extra := onlyIn(sub, super)
+	if len(extra) == 0 {
+		return true
+	}
+	t.Errorf("not a subset: %v not in superset of %d elements", extra, super.Len())
+	return false
+}
+
+// Equal reports whether x and y contain the same elements.
+// It can be passed to cmp.Comparer.
+func Equal[Elem comparable](x, y set.Set[Elem]) bool {
+	return x.Equal(y)
+}
+
+// CmpOption returns a cmp.Option that compares set.Set[Elem] values by
+// their elements, including sets nested inside other values. Sets are
+// shown as sorted slices in cmp.Diff output.
+func CmpOption[Elem comparable]() cmp.Option {
+	return cmp.Transformer("set.Set", func(s set.Set[Elem]) []Elem {
+		r := s.ToSlice()
+		order.Sort(r)
+		return r
+	})
+}
+
+// onlyIn returns the sorted elements of a that are not in b.
+func onlyIn[Elem comparable](a, b set.Interface[Elem]) []Elem {
+	var r []Elem
+	a.Do(func(v Elem) bool {
+		if !b.Contains(v) {
+			r = append(r, v)
+		}
+		return true
+	})
+	order.Sort(r)
+	return r
+}
@@ -0,0 +1,126 @@
+package settest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	set "github.com/tacomeet/go-set"
+)
+
+// recorder is a testing.TB that records errors instead of failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		got  set.Set[int]
+		want set.Set[int]
+		msg  string
+	}{
+		"equal": {
+			got:  set.Of(1, 2, 3),
+			want: set.Of(3, 2, 1),
+		},
+		"initialization and empty": {
+			got:  set.Set[int]{},
+			want: set.Of[int](),
+		},
+		"missing and unexpected": {
+			got:  set.Of(1, 2, 10, 30, 4),
+			want: set.Of(1, 2, 9, 3, 20),
+			msg:  "sets differ (got 5 elements, want 5)\n\tmissing:    [3 9 20]\n\tunexpected: [4 10 30]",
+		},
+		"missing only": {
+			got:  set.Of(1),
+			want: set.Of(1, 2),
+			msg:  "sets differ (got 1 elements, want 2)\n\tmissing:    [2]",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			ok := AssertEqual[int](r, &tt.got, &tt.want)
+			if ok != (tt.msg == "") {
+				t.Fatalf("got %v, want %v", ok, tt.msg == "")
+			}
+			if got := strings.Join(r.errors, "\n"); got != tt.msg {
+				t.Fatalf("got %q, want %q", got, tt.msg)
+			}
+		})
+	}
+}
+
+func TestAssertContains(t *testing.T) {
+	t.Parallel()
+
+	s := set.Of("a", "b")
+	r := &recorder{}
+	if !AssertContains[string](r, &s, "b", "a") {
+		t.Fatalf("got false, want true")
+	}
+	if AssertContains[string](r, &s, "z", "a", "c") {
+		t.Fatalf("got true, want false")
+	}
+	if want := "set of 2 elements is missing [c z]"; len(r.errors) != 1 || r.errors[0] != want {
+		t.Fatalf("got %q, want %q", r.errors, want)
+	}
+}
+
+func TestAssertSubset(t *testing.T) {
+	t.Parallel()
+
+	sub, super := set.Of(1.5, -2.0), set.Of(1.5, 3.0)
+	r := &recorder{}
+	if AssertSubset[float64](r, &sub, &super) {
+		t.Fatalf("got true, want false")
+	}
+	if !AssertSubset[float64](r, &super, &super) {
+		t.Fatalf("got false, want true")
+	}
+	if want := "not a subset: [-2] not in superset of 2 elements"; len(r.errors) != 1 || r.errors[0] != want {
+		t.Fatalf("got %q, want %q", r.errors, want)
+	}
+}
+
+func TestCmpOption(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		Name string
+		Tags set.Set[string]
+	}
+	x := user{Name: "a", Tags: set.Of("x", "y")}
+	y := user{Name: "a", Tags: set.Of("y", "x")}
+	z := user{Name: "a", Tags: set.Of("x", "z")}
+
+	if !cmp.Equal(x, y, CmpOption[string]()) {
+		t.Fatalf("got not equal, want equal: %s", cmp.Diff(x, y, CmpOption[string]()))
+	}
+	if cmp.Equal(x, z, CmpOption[string]()) {
+		t.Fatalf("got equal, want not equal")
+	}
+	if diff := cmp.Diff(x, z, CmpOption[string]()); !strings.Contains(diff, `"y"`) || !strings.Contains(diff, `"z"`) {
+		t.Fatalf("diff does not name the differing elements: %s", diff)
+	}
+	if !cmp.Equal(x, y, cmp.Comparer(Equal[string])) {
+		t.Fatalf("comparer: got not equal, want equal")
+	}
+}
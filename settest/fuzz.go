@@ -0,0 +1,71 @@
+package settest
+
+import (
+	"testing"
+
+	set "github.com/tacomeet/go-set"
+)
+
+// Fuzz registers a fuzz target that applies random sequences of operations
+// to a set created by factory and to a reference model, failing when they
+// disagree. Call it from a fuzz test:
+//
+//	func FuzzMySet(f *testing.F) {
+//		settest.Fuzz(f, factory)
+//	}
+//
+// Each pair of input bytes is decoded as an operation and an element.
+func Fuzz(f *testing.F, factory Factory) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 1, 0, 2, 2, 1, 7, 0})
+	f.Add([]byte{0, 1, 1, 1, 3, 0, 0, 5, 4, 2, 6, 0, 5, 3})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		s := factory.Int()
+		want := model{}
+		for i := 0; i+1 < len(ops); i += 2 {
+			v := int(ops[i+1] % 16)
+			switch ops[i] % 8 {
+			case 0:
+				s.Add(v)
+				want[v] = struct{}{}
+			case 1:
+				s.Remove(v)
+				delete(want, v)
+			case 2:
+				o := set.Of(v, v+1)
+				s.AddSet(&o)
+				want[v], want[v+1] = struct{}{}, struct{}{}
+			case 3:
+				o := set.Of(v, v+1)
+				s.RemoveSet(&o)
+				delete(want, v)
+				delete(want, v+1)
+			case 4:
+				s.Retain(func(e int) bool { return e%(v+1) != 0 })
+				for e := range want {
+					if e%(v+1) == 0 {
+						delete(want, e)
+					}
+				}
+			case 5:
+				got, ok := s.Pop()
+				if _, found := want[got]; ok != (len(want) > 0) || (ok && !found) {
+					t.Fatalf("Pop() = %v, %v on %v", got, ok, want.sorted())
+				}
+				delete(want, got)
+			case 6:
+				c := s.Clone()
+				if c.Len() != len(want) || !s.Equal(c) {
+					t.Fatalf("Clone() = %v, want %v", c.ToSlice(), want.sorted())
+				}
+			case 7:
+				s.Clear()
+				want = model{}
+			}
+			if _, ok := want[v]; s.Contains(v) != ok {
+				t.Fatalf("Contains(%v) = %v, want %v", v, !ok, ok)
+			}
+		}
+		check(t, "after operations", s, want)
+	})
+}
@@ -0,0 +1,96 @@
+package settest
+
+import (
+	"math/rand"
+	"testing"
+
+	set "github.com/tacomeet/go-set"
+)
+
+// lawIterations is the number of random inputs each law is checked with.
+const lawIterations = 200
+
+// lawUniverse bounds the elements of random sets,
+// keeping them small enough to overlap often.
+const lawUniverse = 24
+
+// testLaws checks the algebraic laws of Union, Intersect and Difference
+// on random sets built by factory.
+func testLaws(t *testing.T, factory Factory) {
+	laws := map[string]func(a, b, c, u set.Interface[int]) (set.Set[int], set.Set[int]){
+		"union commutativity": func(a, b, _, _ set.Interface[int]) (set.Set[int], set.Set[int]) {
+			return set.Union(a, b), set.Union(b, a)
+		},
+		"intersection commutativity": func(a, b, _, _ set.Interface[int]) (set.Set[int], set.Set[int]) {
+			return set.Intersect(a, b), set.Intersect(b, a)
+		},
+		"union associativity": func(a, b, c, _ set.Interface[int]) (set.Set[int], set.Set[int]) {
+			ab, bc := set.Union(a, b), set.Union(b, c)
+			return set.Union(&ab, c), set.Union(a, &bc)
+		},
+		"intersection associativity": func(a, b, c, _ set.Interface[int]) (set.Set[int], set.Set[int]) {
+			ab, bc := set.Intersect(a, b), set.Intersect(b, c)
+			return set.Intersect(&ab, c), set.Intersect(a, &bc)
+		},
+		"union absorption": func(a, b, _, _ set.Interface[int]) (set.Set[int], set.Set[int]) {
+			ab := set.Intersect(a, b)
+			return set.Union(a, &ab), copyOf(a)
+		},
+		"intersection absorption": func(a, b, _, _ set.Interface[int]) (set.Set[int], set.Set[int]) {
+			ab := set.Union(a, b)
+			return set.Intersect(a, &ab), copyOf(a)
+		},
+		"de morgan union": func(a, b, _, u set.Interface[int]) (set.Set[int], set.Set[int]) {
+			ab := set.Union(a, b)
+			na, nb := set.Difference(u, a), set.Difference(u, b)
+			return set.Difference(u, &ab), set.Intersect(&na, &nb)
+		},
+		"de morgan intersection": func(a, b, _, u set.Interface[int]) (set.Set[int], set.Set[int]) {
+			ab := set.Intersect(a, b)
+			na, nb := set.Difference(u, a), set.Difference(u, b)
+			return set.Difference(u, &ab), set.Union(&na, &nb)
+		},
+		"union distributes over intersection": func(a, b, c, _ set.Interface[int]) (set.Set[int], set.Set[int]) {
+			bc, ab, ac := set.Intersect(b, c), set.Union(a, b), set.Union(a, c)
+			return set.Union(a, &bc), set.Intersect(&ab, &ac)
+		},
+		"difference is intersection with complement": func(a, b, _, u set.Interface[int]) (set.Set[int], set.Set[int]) {
+			nb := set.Difference(u, b)
+			return set.Difference(a, b), set.Intersect(a, &nb)
+		},
+	}
+
+	u := factory.Int()
+	for i := 0; i < lawUniverse; i++ {
+		u.Add(i)
+	}
+	for name, law := range laws {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < lawIterations; i++ {
+			a := randomSet(r, factory, lawUniverse)
+			b := randomSet(r, factory, lawUniverse)
+			c := randomSet(r, factory, lawUniverse)
+			lhs, rhs := law(a, b, c, u)
+			if !lhs.Equal(rhs) {
+				t.Errorf("%s: a=%v b=%v c=%v: %v != %v",
+					name, sorted(a), sorted(b), sorted(c), sorted(&lhs), sorted(&rhs))
+				break
+			}
+		}
+	}
+}
+
+func copyOf(s set.Interface[int]) set.Set[int] {
+	var r set.Set[int]
+	r.AddSet(s)
+	return r
+}
+
+func sorted(s set.Interface[int]) []int {
+	m := model{}
+	s.Do(func(v int) bool {
+		m[v] = struct{}{}
+		return true
+	})
+	return m.sorted()
+}
@@ -0,0 +1,325 @@
+// Package settest provides test helpers for sets: assertions with readable
+// diffs, a go-cmp option, and a conformance suite for set implementations.
+//
+// An implementation passes the suite if it behaves exactly like set.Set:
+// the suite checks every method against a reference model, checks the
+// algebraic laws of the package level operations with randomized inputs,
+// and provides a fuzz target for sequences of operations.
+package settest
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	set "github.com/tacomeet/go-set"
+)
+
+// Set is the method set of set.Set that an implementation must provide
+// to be checked by Run.
+type Set[Elem comparable] interface {
+	set.Interface[Elem]
+	Add(v ...Elem)
+	AddSet(s2 set.Interface[Elem])
+	Remove(v ...Elem)
+	RemoveSet(s2 set.Interface[Elem])
+	ContainsAny(s2 set.Interface[Elem]) bool
+	ContainsAll(s2 set.Interface[Elem]) bool
+	ToSlice() []Elem
+	Equal(s2 set.Set[Elem]) bool
+	Clear()
+	Clone() set.Set[Elem]
+	Retain(keep func(Elem) bool)
+	Pop() (Elem, bool)
+}
+
+// Factory creates sets of the implementation under test.
+// Each function must return a new, empty set; if the implementation's
+// zero value is usable, returning the zero value checks that too.
+type Factory struct {
+	Int func() Set[int]
+	// Float is optional. If it is not nil, Run also checks that
+	// adding NaN panics.
+	Float func() Set[float64]
+}
+
+const nanPanic = "element in set has to be equal to itself"
+
+// model is the reference implementation the suite compares against.
+type model map[int]struct{}
+
+func modelOf(v ...int) model {
+	m := model{}
+	for _, v := range v {
+		m[v] = struct{}{}
+	}
+	return m
+}
+
+func (m model) sorted() []int {
+	r := make([]int, 0, len(m))
+	for k := range m {
+		r = append(r, k)
+	}
+	slices.Sort(r)
+	return r
+}
+
+// Run runs the conformance suite against the implementation
+// created by factory.
+func Run(t *testing.T, factory Factory) {
+	t.Helper()
+	t.Run("ZeroValue", func(t *testing.T) { testZeroValue(t, factory) })
+	t.Run("Add", func(t *testing.T) { testAdd(t, factory) })
+	t.Run("AddSet", func(t *testing.T) { testAddSet(t, factory) })
+	t.Run("Remove", func(t *testing.T) { testRemove(t, factory) })
+	t.Run("RemoveSet", func(t *testing.T) { testRemoveSet(t, factory) })
+	t.Run("ContainsAnyAll", func(t *testing.T) { testContainsAnyAll(t, factory) })
+	t.Run("Equal", func(t *testing.T) { testEqual(t, factory) })
+	t.Run("Clear", func(t *testing.T) { testClear(t, factory) })
+	t.Run("Clone", func(t *testing.T) { testClone(t, factory) })
+	t.Run("Retain", func(t *testing.T) { testRetain(t, factory) })
+	t.Run("Do", func(t *testing.T) { testDo(t, factory) })
+	t.Run("Pop", func(t *testing.T) { testPop(t, factory) })
+	t.Run("NaN", func(t *testing.T) { testNaN(t, factory) })
+	t.Run("Laws", func(t *testing.T) { testLaws(t, factory) })
+}
+
+func build(factory Factory, v ...int) Set[int] {
+	s := factory.Int()
+	s.Add(v...)
+	return s
+}
+
+// check reports an error if s does not hold exactly the elements of want.
+func check(t *testing.T, what string, s Set[int], want model) {
+	t.Helper()
+	if s.Len() != len(want) {
+		t.Errorf("%s: Len() = %v, want %v", what, s.Len(), len(want))
+	}
+	for k := range want {
+		if !s.Contains(k) {
+			t.Errorf("%s: Contains(%v) = false, want true", what, k)
+		}
+	}
+	got := s.ToSlice()
+	slices.Sort(got)
+	if !slices.Equal(got, want.sorted()) {
+		t.Errorf("%s: ToSlice() = %v, want %v", what, got, want.sorted())
+	}
+}
+
+func testZeroValue(t *testing.T, factory Factory) {
+	ops := map[string]func(s Set[int]){
+		"Remove":    func(s Set[int]) { s.Remove(1) },
+		"RemoveSet": func(s Set[int]) { o := set.Of(1); s.RemoveSet(&o) },
+		"Clear":     func(s Set[int]) { s.Clear() },
+		"Retain":    func(s Set[int]) { s.Retain(func(int) bool { return false }) },
+		"Pop":       func(s Set[int]) { s.Pop() },
+		"Do":        func(s Set[int]) { s.Do(func(int) bool { return true }) },
+	}
+	for name, op := range ops {
+		s := factory.Int()
+		op(s)
+		check(t, name+" on empty set", s, model{})
+		if s.Contains(1) {
+			t.Errorf("%s on empty set: Contains(1) = true, want false", name)
+		}
+	}
+	s := factory.Int()
+	if c := s.Clone(); c.Len() != 0 {
+		t.Errorf("Clone of empty set: Len() = %v, want 0", c.Len())
+	}
+	if !s.Equal(set.Of[int]()) {
+		t.Errorf("empty set not equal to set.Of()")
+	}
+	s.Add(1)
+	check(t, "Add on empty set", s, modelOf(1))
+}
+
+func testAdd(t *testing.T, factory Factory) {
+	s := factory.Int()
+	s.Add()
+	check(t, "Add()", s, model{})
+	s.Add(1, 2, 2, 3)
+	check(t, "Add(1, 2, 2, 3)", s, modelOf(1, 2, 3))
+	s.Add(3, 4)
+	check(t, "Add(3, 4)", s, modelOf(1, 2, 3, 4))
+}
+
+func testAddSet(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2)
+	other := set.Of(2, 3)
+	s.AddSet(&other)
+	check(t, "AddSet(*set.Set)", s, modelOf(1, 2, 3))
+	s.AddSet(build(factory, 4))
+	check(t, "AddSet(implementation)", s, modelOf(1, 2, 3, 4))
+	s.AddSet(factory.Int())
+	check(t, "AddSet(empty)", s, modelOf(1, 2, 3, 4))
+}
+
+func testRemove(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2, 3)
+	s.Remove(2, 5)
+	check(t, "Remove(2, 5)", s, modelOf(1, 3))
+	s.Remove()
+	check(t, "Remove()", s, modelOf(1, 3))
+	s.Remove(1, 3)
+	check(t, "Remove(1, 3)", s, model{})
+}
+
+func testRemoveSet(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2, 3, 4)
+	other := set.Of(1, 5)
+	s.RemoveSet(&other)
+	check(t, "RemoveSet(*set.Set)", s, modelOf(2, 3, 4))
+	s.RemoveSet(build(factory, 2, 3))
+	check(t, "RemoveSet(implementation)", s, modelOf(4))
+}
+
+func testContainsAnyAll(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2, 3)
+	tests := []struct {
+		arg     []int
+		wantAny bool
+		wantAll bool
+	}{
+		{arg: nil, wantAny: false, wantAll: true},
+		{arg: []int{1}, wantAny: true, wantAll: true},
+		{arg: []int{1, 2, 3}, wantAny: true, wantAll: true},
+		{arg: []int{3, 4}, wantAny: true, wantAll: false},
+		{arg: []int{4, 5}, wantAny: false, wantAll: false},
+		{arg: []int{1, 2, 3, 4}, wantAny: true, wantAll: false},
+	}
+	for _, tt := range tests {
+		o := set.Of(tt.arg...)
+		for _, arg := range []set.Interface[int]{build(factory, tt.arg...), &o} {
+			if got := s.ContainsAny(arg); got != tt.wantAny {
+				t.Errorf("ContainsAny(%v) = %v, want %v", tt.arg, got, tt.wantAny)
+			}
+			if got := s.ContainsAll(arg); got != tt.wantAll {
+				t.Errorf("ContainsAll(%v) = %v, want %v", tt.arg, got, tt.wantAll)
+			}
+		}
+	}
+}
+
+func testEqual(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2, 3)
+	tests := []struct {
+		arg  set.Set[int]
+		want bool
+	}{
+		{arg: set.Of(1, 2, 3), want: true},
+		{arg: set.Of(1, 2), want: false},
+		{arg: set.Of(1, 2, 4), want: false},
+		{arg: set.Of(1, 2, 3, 4), want: false},
+		{arg: set.Set[int]{}, want: false},
+	}
+	for _, tt := range tests {
+		if got := s.Equal(tt.arg); got != tt.want {
+			t.Errorf("Equal(%v) = %v, want %v", tt.arg.ToSlice(), got, tt.want)
+		}
+	}
+}
+
+func testClear(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2, 3)
+	s.Clear()
+	check(t, "Clear()", s, model{})
+	s.Add(4)
+	check(t, "Add after Clear()", s, modelOf(4))
+}
+
+func testClone(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2, 3)
+	c := s.Clone()
+	c.Add(4)
+	s.Remove(1)
+	check(t, "original after Clone()", s, modelOf(2, 3))
+	if want := set.Of(1, 2, 3, 4); !want.Equal(c) {
+		t.Errorf("clone: got %v, want %v", c.ToSlice(), want.ToSlice())
+	}
+}
+
+func testRetain(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2, 3, 4, 5)
+	s.Retain(func(v int) bool { return v%2 == 1 })
+	check(t, "Retain(odd)", s, modelOf(1, 3, 5))
+	s.Retain(func(int) bool { return true })
+	check(t, "Retain(all)", s, modelOf(1, 3, 5))
+	s.Retain(func(int) bool { return false })
+	check(t, "Retain(none)", s, model{})
+}
+
+func testDo(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2, 3, 4, 5)
+	seen := model{}
+	s.Do(func(v int) bool {
+		if _, ok := seen[v]; ok {
+			t.Errorf("Do: %v visited twice", v)
+		}
+		seen[v] = struct{}{}
+		return true
+	})
+	if !slices.Equal(seen.sorted(), []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Do: visited %v, want %v", seen.sorted(), []int{1, 2, 3, 4, 5})
+	}
+	calledTimes := 0
+	s.Do(func(int) bool {
+		calledTimes++
+		return calledTimes < 2
+	})
+	if calledTimes != 2 {
+		t.Errorf("Do stopped after %v calls, want 2", calledTimes)
+	}
+}
+
+func testPop(t *testing.T, factory Factory) {
+	s := build(factory, 1, 2, 3)
+	want := modelOf(1, 2, 3)
+	for len(want) > 0 {
+		v, ok := s.Pop()
+		if !ok {
+			t.Fatalf("Pop() on %v returned false", want.sorted())
+		}
+		if _, ok := want[v]; !ok {
+			t.Fatalf("Pop() = %v, want one of %v", v, want.sorted())
+		}
+		delete(want, v)
+		check(t, "Pop()", s, want)
+	}
+	if v, ok := s.Pop(); ok || v != 0 {
+		t.Errorf("Pop() on empty set = %v, %v, want 0, false", v, ok)
+	}
+}
+
+func testNaN(t *testing.T, factory Factory) {
+	if factory.Float == nil {
+		t.Skip("Factory.Float is nil")
+	}
+	for name, args := range map[string][]float64{
+		"one element":         {math.NaN()},
+		"with other elements": {1, 2, math.NaN()},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != nanPanic {
+					t.Errorf("Add(%s): recovered %v, want %q", name, r, nanPanic)
+				}
+			}()
+			factory.Float().Add(args...)
+		}()
+	}
+}
+
+// randomSet returns a set with random elements from [0, universe).
+func randomSet(r *rand.Rand, factory Factory, universe int) Set[int] {
+	s := factory.Int()
+	n := r.Intn(universe)
+	for i := 0; i < n; i++ {
+		s.Add(r.Intn(universe))
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package settest
+
+import (
+	"encoding/json"
+	"testing"
+
+	set "github.com/tacomeet/go-set"
+)
+
+var factory = Factory{
+	Int:   func() Set[int] { return &set.Set[int]{} },
+	Float: func() Set[float64] { return &set.Set[float64]{} },
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	Run(t, factory)
+}
+
+func TestSetOf(t *testing.T) {
+	t.Parallel()
+
+	Run(t, Factory{
+		Int: func() Set[int] {
+			s := set.Of[int]()
+			return &s
+		},
+	})
+}
+
+func TestHashSet(t *testing.T) {
+	t.Parallel()
+
+	Run(t, Factory{
+		Int:   func() Set[int] { return &set.HashSet[int]{} },
+		Float: func() Set[float64] { return &set.HashSet[float64]{} },
+	})
+}
+
+func TestHashSetCollisions(t *testing.T) {
+	t.Parallel()
+
+	Run(t, Factory{
+		Int: func() Set[int] {
+			return set.NewHashSetFunc(0, func(v int) uint64 { return uint64(v % 3) })
+		},
+	})
+}
+
+func FuzzSet(f *testing.F) {
+	Fuzz(f, factory)
+}
+
+func FuzzDeltaUnmarshalJSON(f *testing.F) {
+	f.Add([]byte(`{"added":[1,2],"removed":[3]}`))
+	f.Add([]byte(`{"added":[1],"removed":[1]}`))
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`null`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var d set.Delta[int]
+		if err := json.Unmarshal(data, &d); err != nil {
+			return
+		}
+		if d.Added.ContainsAny(&d.Removed) {
+			t.Fatalf("decoded delta adds and removes the same element: %s", data)
+		}
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var again set.Delta[int]
+		if err := json.Unmarshal(b, &again); err != nil {
+			t.Fatalf("decoding %s: %v", b, err)
+		}
+		if !d.Added.Equal(again.Added) || !d.Removed.Equal(again.Removed) {
+			t.Fatalf("round trip of %s: got %s", data, b)
+		}
+	})
+}
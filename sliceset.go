@@ -0,0 +1,167 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SliceSet is an immutable set of ordered elements stored as a sorted
+// slice. It uses less memory than Set and is faster to iterate, at the
+// cost of O(log n) lookups; it suits sets that are built once and read
+// many times.
+// The zero value is an empty set.
+type SliceSet[Elem cmp.Ordered] struct {
+	elems []Elem
+}
+
+// SliceOf returns a new sorted-slice set containing the listed elements.
+func SliceOf[Elem cmp.Ordered](v ...Elem) SliceSet[Elem] {
+	r := make([]Elem, len(v))
+	copy(r, v)
+	return newSliceSet(r)
+}
+
+// FromSet returns a sorted-slice set with the elements of s.
+// It takes O(n log n) time.
+func FromSet[Elem cmp.Ordered](s *Set[Elem]) SliceSet[Elem] {
+	return newSliceSet(s.ToSlice())
+}
+
+// newSliceSet sorts v and removes duplicates in place.
+func newSliceSet[Elem cmp.Ordered](v []Elem) SliceSet[Elem] {
+	for _, v := range v {
+		if v != v {
+			panic("element in set has to be equal to itself")
+		}
+	}
+	slices.Sort(v)
+	return SliceSet[Elem]{elems: slices.Compact(v)}
+}
+
+// ToSet returns the elements of s as a Set.
+func (s SliceSet[Elem]) ToSet() Set[Elem] {
+	r := WithCap[Elem](len(s.elems))
+	for _, v := range s.elems {
+		r.m[v] = struct{}{}
+	}
+	return r
+}
+
+// Contains reports whether v is in the set.
+func (s SliceSet[Elem]) Contains(v Elem) bool {
+	_, ok := slices.BinarySearch(s.elems, v)
+	return ok
+}
+
+// Len returns the number of elements in s.
+func (s SliceSet[Elem]) Len() int {
+	return len(s.elems)
+}
+
+// Do calls f on every element in the set s in increasing order,
+// stopping if f returns false.
+func (s SliceSet[Elem]) Do(f func(Elem) bool) {
+	for _, v := range s.elems {
+		if !f(v) {
+			break
+		}
+	}
+}
+
+// ToSlice returns the elements in the set s as a slice in increasing order.
+func (s SliceSet[Elem]) ToSlice() []Elem {
+	return slices.Clone(s.elems)
+}
+
+// Equal reports whether s and s2 contain the same elements.
+func (s SliceSet[Elem]) Equal(s2 SliceSet[Elem]) bool {
+	return slices.Equal(s.elems, s2.elems)
+}
+
+// SliceUnion constructs a new set containing the union of s1 and s2
+// by merging them in linear time.
+func SliceUnion[Elem cmp.Ordered](s1, s2 SliceSet[Elem]) SliceSet[Elem] {
+	a, b := s1.elems, s2.elems
+	r := make([]Elem, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := cmp.Compare(a[i], b[j]); {
+		case c < 0:
+			r = append(r, a[i])
+			i++
+		case c > 0:
+			r = append(r, b[j])
+			j++
+		default:
+			r = append(r, a[i])
+			i++
+			j++
+		}
+	}
+	r = append(r, a[i:]...)
+	r = append(r, b[j:]...)
+	return SliceSet[Elem]{elems: r}
+}
+
+// SliceDifference constructs a new set containing the elements of s1
+// that are not in s2 by merging them in linear time.
+func SliceDifference[Elem cmp.Ordered](s1, s2 SliceSet[Elem]) SliceSet[Elem] {
+	a, b := s1.elems, s2.elems
+	r := make([]Elem, 0, len(a))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := cmp.Compare(a[i], b[j]); {
+		case c < 0:
+			r = append(r, a[i])
+			i++
+		case c > 0:
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	r = append(r, a[i:]...)
+	return SliceSet[Elem]{elems: r}
+}
+
+// SliceIntersect constructs a new set containing the intersection of
+// s1 and s2. It walks the smaller set and finds each element in the
+// larger one by galloping (exponential search) from the previous match,
+// so it takes O(m log(n/m)) time for sets of sizes m <= n.
+func SliceIntersect[Elem cmp.Ordered](s1, s2 SliceSet[Elem]) SliceSet[Elem] {
+	small, large := s1.elems, s2.elems
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	r := make([]Elem, 0, len(small))
+	lo := 0
+	for _, v := range small {
+		lo = gallop(large, lo, v)
+		if lo == len(large) {
+			break
+		}
+		if large[lo] == v {
+			r = append(r, v)
+			lo++
+		}
+	}
+	return SliceSet[Elem]{elems: r}
+}
+
+// gallop returns the smallest index i >= lo such that a[i] >= v,
+// or len(a) if there is none.
+func gallop[Elem cmp.Ordered](a []Elem, lo int, v Elem) int {
+	step := 1
+	hi := lo
+	for hi < len(a) && a[hi] < v {
+		lo = hi + 1
+		hi += step
+		step *= 2
+	}
+	if hi > len(a) {
+		hi = len(a)
+	}
+	i, _ := slices.BinarySearch(a[lo:hi], v)
+	return lo + i
+}
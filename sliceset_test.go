@@ -0,0 +1,138 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+var _ Interface[int] = SliceSet[int]{}
+
+func TestSliceOf(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args []int
+		want []int
+	}{
+		"empty":      {args: []int{}, want: []int{}},
+		"sorted":     {args: []int{1, 2, 3}, want: []int{1, 2, 3}},
+		"unsorted":   {args: []int{3, 1, 2}, want: []int{1, 2, 3}},
+		"duplicates": {args: []int{2, 1, 2, 1}, want: []int{1, 2}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			s := SliceOf(tt.args...)
+			if got := s.ToSlice(); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for _, v := range tt.want {
+				if !s.Contains(v) {
+					t.Fatalf("contains %v: got false, want true", v)
+				}
+			}
+			if s.Contains(100) {
+				t.Fatalf("contains 100: got true, want false")
+			}
+		})
+	}
+}
+
+func TestSliceSetOperations(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		arg1 SliceSet[int]
+		arg2 SliceSet[int]
+	}{
+		"initialization and empty": {
+			arg1: SliceSet[int]{},
+			arg2: SliceSet[int]{},
+		},
+		"one empty": {
+			arg1: SliceOf(1, 2, 3),
+			arg2: SliceSet[int]{},
+		},
+		"same elements": {
+			arg1: SliceOf(1, 2, 3),
+			arg2: SliceOf(1, 2, 3),
+		},
+		"different elements": {
+			arg1: SliceOf(1, 2, 3),
+			arg2: SliceOf(4, 5, 6),
+		},
+		"interleaved": {
+			arg1: SliceOf(1, 3, 5, 7, 9),
+			arg2: SliceOf(2, 3, 4, 9, 10),
+		},
+		"skewed": {
+			arg1: SliceOf(0, 500, 999, 2000),
+			arg2: func() SliceSet[int] {
+				s := Set[int]{}
+				for i := 0; i < 1000; i++ {
+					s.Add(i)
+				}
+				return FromSet(&s)
+			}(),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			s1, s2 := tt.arg1.ToSet(), tt.arg2.ToSet()
+			check := func(op string, got SliceSet[int], want Set[int]) {
+				t.Helper()
+				if !slices.IsSorted(got.ToSlice()) || !want.Equal(got.ToSet()) {
+					t.Fatalf("%s: got %v, want %v", op, got.ToSlice(), want)
+				}
+			}
+			check("union", SliceUnion(tt.arg1, tt.arg2), Union(&s1, &s2))
+			check("intersect", SliceIntersect(tt.arg1, tt.arg2), Intersect(&s1, &s2))
+			check("intersect reversed", SliceIntersect(tt.arg2, tt.arg1), Intersect(&s1, &s2))
+			check("difference", SliceDifference(tt.arg1, tt.arg2), Difference(&s1, &s2))
+			check("difference reversed", SliceDifference(tt.arg2, tt.arg1), Difference(&s2, &s1))
+		})
+	}
+}
+
+func TestSliceOfWithNaN(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "element in set has to be equal to itself" {
+			t.Fatalf("got %v, want %v", r, "element in set has to be equal to itself")
+		}
+	}()
+	SliceOf(1.0, math.NaN())
+}
+
+func BenchmarkSliceIntersect(b *testing.B) {
+	large := make([]int, 1<<20)
+	for i := range large {
+		large[i] = i * 2
+	}
+	ls := SliceOf(large...)
+	lm := ls.ToSet()
+	for _, small := range []int{16, 1 << 10, 1 << 16} {
+		v := make([]int, small)
+		for i := range v {
+			v[i] = i * (len(large) / small) * 3
+		}
+		ss := SliceOf(v...)
+		sm := ss.ToSet()
+		b.Run(fmt.Sprintf("small=%d/slice", small), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				SliceIntersect(ss, ls)
+			}
+		})
+		b.Run(fmt.Sprintf("small=%d/map", small), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Intersect(&sm, &lm)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package set
+
+// smallCap is the number of elements a SmallSet stores inline.
+const smallCap = 4
+
+// SmallSet is a set optimized for holding only a few elements.
+// Up to 4 elements are stored inline and found by linear scan, so small
+// sets need no allocation beyond the SmallSet itself. When a fifth
+// element is added the elements move to a map, which is kept until
+// Clear is called.
+// The zero value is an empty set.
+//
+// Like Set, copying a SmallSet that has grown past the inline storage
+// shares the map between the copies.
+type SmallSet[Elem comparable] struct {
+	// n is the number of elements in small. It is unused once m is set.
+	n     int
+	small [smallCap]Elem
+	m     map[Elem]struct{}
+}
+
+// SmallOf returns a new small set containing the listed elements.
+func SmallOf[Elem comparable](v ...Elem) SmallSet[Elem] {
+	var s SmallSet[Elem]
+	s.Add(v...)
+	return s
+}
+
+// Add adds elements to a set.
+func (s *SmallSet[Elem]) Add(v ...Elem) {
+	for _, v := range v {
+		if v != v {
+			panic("element in set has to be equal to itself")
+		}
+		s.add(v)
+	}
+}
+
+func (s *SmallSet[Elem]) add(v Elem) {
+	if s.m != nil {
+		s.m[v] = struct{}{}
+		return
+	}
+	if s.index(v) >= 0 {
+		return
+	}
+	if s.n < smallCap {
+		s.small[s.n] = v
+		s.n++
+		return
+	}
+	s.m = make(map[Elem]struct{}, 2*smallCap)
+	for _, k := range s.small {
+		s.m[k] = struct{}{}
+	}
+	s.m[v] = struct{}{}
+	s.small = [smallCap]Elem{}
+	s.n = 0
+}
+
+// AddSet adds the elements of set s2 to s.
+func (s *SmallSet[Elem]) AddSet(s2 Interface[Elem]) {
+	s2.Do(func(v Elem) bool {
+		s.add(v)
+		return true
+	})
+}
+
+// Remove removes elements from a set.
+// Elements that are not present are ignored.
+func (s *SmallSet[Elem]) Remove(v ...Elem) {
+	for _, v := range v {
+		if s.m != nil {
+			delete(s.m, v)
+			continue
+		}
+		if i := s.index(v); i >= 0 {
+			s.n--
+			s.small[i] = s.small[s.n]
+			var zero Elem
+			s.small[s.n] = zero
+		}
+	}
+}
+
+// index returns the position of v in the inline storage, or -1.
+func (s *SmallSet[Elem]) index(v Elem) int {
+	for i := 0; i < s.n; i++ {
+		if s.small[i] == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains reports whether v is in the set.
+func (s *SmallSet[Elem]) Contains(v Elem) bool {
+	if s.m != nil {
+		_, ok := s.m[v]
+		return ok
+	}
+	return s.index(v) >= 0
+}
+
+// Len returns the number of elements in s.
+func (s *SmallSet[Elem]) Len() int {
+	if s.m != nil {
+		return len(s.m)
+	}
+	return s.n
+}
+
+// Do calls f on every element in the set s,
+// stopping if f returns false.
+// f should not change s.
+// f will be called on values in an indeterminate order.
+func (s *SmallSet[Elem]) Do(f func(Elem) bool) {
+	if s.m != nil {
+		for k := range s.m {
+			if !f(k) {
+				break
+			}
+		}
+		return
+	}
+	for _, v := range s.small[:s.n] {
+		if !f(v) {
+			break
+		}
+	}
+}
+
+// ToSlice returns the elements in the set s as a slice.
+// The values will be in an indeterminate order.
+func (s *SmallSet[Elem]) ToSlice() []Elem {
+	r := make([]Elem, 0, s.Len())
+	s.Do(func(v Elem) bool {
+		r = append(r, v)
+		return true
+	})
+	return r
+}
+
+// ToSet returns the elements of s as a Set.
+func (s *SmallSet[Elem]) ToSet() Set[Elem] {
+	r := WithCap[Elem](s.Len())
+	r.AddSet(s)
+	return r
+}
+
+// Clone returns a copy of s.
+// The elements are copied using assignment,
+// so this is a shallow clone.
+func (s *SmallSet[Elem]) Clone() SmallSet[Elem] {
+	r := *s
+	if s.m != nil {
+		r.m = make(map[Elem]struct{}, len(s.m))
+		for k := range s.m {
+			r.m[k] = struct{}{}
+		}
+	}
+	return r
+}
+
+// Clear removes all elements from s, leaving it empty and
+// releasing any map it allocated.
+func (s *SmallSet[Elem]) Clear() {
+	*s = SmallSet[Elem]{}
+}
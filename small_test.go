@@ -0,0 +1,170 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSmallSet(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		ops      func(s *SmallSet[int])
+		want     Set[int]
+		wantHeap bool
+	}{
+		"zero value": {
+			ops:  func(s *SmallSet[int]) {},
+			want: Of[int](),
+		},
+		"inline": {
+			ops:  func(s *SmallSet[int]) { s.Add(1, 2, 3, 4) },
+			want: Of(1, 2, 3, 4),
+		},
+		"inline duplicates": {
+			ops:  func(s *SmallSet[int]) { s.Add(1, 1, 2, 2, 3, 3, 4, 4) },
+			want: Of(1, 2, 3, 4),
+		},
+		"promoted": {
+			ops:      func(s *SmallSet[int]) { s.Add(1, 2, 3, 4, 5) },
+			want:     Of(1, 2, 3, 4, 5),
+			wantHeap: true,
+		},
+		"inline remove": {
+			ops: func(s *SmallSet[int]) {
+				s.Add(1, 2, 3, 4)
+				s.Remove(1, 4, 10)
+				s.Add(5)
+			},
+			want: Of(2, 3, 5),
+		},
+		"promoted remove": {
+			ops: func(s *SmallSet[int]) {
+				s.Add(1, 2, 3, 4, 5, 6)
+				s.Remove(1, 2, 3, 10)
+			},
+			want:     Of(4, 5, 6),
+			wantHeap: true,
+		},
+		"clear releases map": {
+			ops: func(s *SmallSet[int]) {
+				s.Add(1, 2, 3, 4, 5, 6)
+				s.Clear()
+				s.Add(7)
+			},
+			want: Of(7),
+		},
+		"add set": {
+			ops: func(s *SmallSet[int]) {
+				s.Add(1)
+				s2 := Of(1, 2, 3)
+				s.AddSet(&s2)
+			},
+			want: Of(1, 2, 3),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var s SmallSet[int]
+			tt.ops(&s)
+			got := s.ToSet()
+			if !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if s.Len() != tt.want.Len() {
+				t.Fatalf("len: got %v, want %v", s.Len(), tt.want.Len())
+			}
+			for _, v := range tt.want.ToSlice() {
+				if !s.Contains(v) {
+					t.Fatalf("contains %v: got false, want true", v)
+				}
+			}
+			if s.Contains(100) {
+				t.Fatalf("contains 100: got true, want false")
+			}
+			if heap := s.m != nil; heap != tt.wantHeap {
+				t.Fatalf("map allocated: got %v, want %v", heap, tt.wantHeap)
+			}
+		})
+	}
+}
+
+func TestSmallSetClone(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{2, 6} {
+		s := SmallSet[int]{}
+		for i := 0; i < n; i++ {
+			s.Add(i)
+		}
+		c := s.Clone()
+		c.Add(100)
+		if s.Contains(100) {
+			t.Fatalf("n=%d: clone shares storage with the original", n)
+		}
+		if c.Len() != n+1 {
+			t.Fatalf("n=%d: got %v, want %v", n, c.Len(), n+1)
+		}
+	}
+}
+
+func TestSmallSetAddWithNaN(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "element in set has to be equal to itself" {
+			t.Fatalf("got %v, want %v", r, "element in set has to be equal to itself")
+		}
+	}()
+	var s SmallSet[float64]
+	s.Add(math.NaN())
+}
+
+func TestSmallSetAllocs(t *testing.T) {
+	var s SmallSet[int]
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Clear()
+		s.Add(1, 2, 3, 4)
+		s.Remove(2)
+	})
+	if allocs != 0 {
+		t.Fatalf("got %v allocations, want 0", allocs)
+	}
+}
+
+var (
+	sinkSet      *Set[int]
+	sinkSmallSet *SmallSet[int]
+)
+
+// BenchmarkSmallSet reports the allocations and bytes needed to build one
+// set of n elements, including the set header itself.
+func BenchmarkSmallSet(b *testing.B) {
+	for _, n := range []int{0, 1, 4, 5, 16} {
+		b.Run(fmt.Sprintf("Set/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s := new(Set[int])
+				for j := 0; j < n; j++ {
+					s.Add(j)
+				}
+				sinkSet = s
+			}
+		})
+		b.Run(fmt.Sprintf("SmallSet/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s := new(SmallSet[int])
+				for j := 0; j < n; j++ {
+					s.Add(j)
+				}
+				sinkSmallSet = s
+			}
+		})
+	}
+}
@@ -0,0 +1,463 @@
+package set
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// StreamFormat selects the format written by an Encoder and read by a
+// Decoder.
+type StreamFormat int
+
+const (
+	// Binary writes each element encoded by the stream's Codec, prefixed
+	// with its length, followed by the number of elements and a CRC-32C
+	// checksum of the whole stream.
+	Binary StreamFormat = iota
+	// JSONLines writes each element as JSON on a line of its own.
+	JSONLines
+	// JSONArray writes the elements as a single JSON array.
+	JSONArray
+)
+
+// StreamOptions configures an Encoder or Decoder.
+type StreamOptions[Elem comparable] struct {
+	// Format is the stream format. The default is Binary.
+	Format StreamFormat
+	// Codec encodes the elements in the Binary format. It is required
+	// for that format and ignored by the others, which use encoding/json.
+	Codec Codec[Elem]
+}
+
+const (
+	streamMagic      = "GOSETST1"
+	streamDeltaMagic = "GOSETSD1"
+	// deltaChunk is the most deltas written in one chunk.
+	deltaChunk = 128
+	// maxStreamElem is the largest element a Decoder reads.
+	maxStreamElem = 16 << 20
+)
+
+// ErrChecksum is returned by a Decoder when a stream's checksum or
+// element count doesn't match its contents.
+var ErrChecksum = errors.New("set: stream checksum mismatch")
+
+// An Encoder writes set elements to a stream one at a time, so that a
+// set can be written without first being copied to a slice. Close must
+// be called to end the stream.
+type Encoder[Elem comparable] struct {
+	w     *bufio.Writer
+	crc   uint32
+	count uint64
+	buf   []byte
+	err   error
+	// encode appends the encoding of an element to buf, and end appends
+	// whatever ends the elements.
+	encode func(v Elem)
+	end    func()
+	// checksum reports whether the stream ends with a count and checksum.
+	checksum bool
+}
+
+// NewEncoder returns an encoder that writes to w in the given format.
+func NewEncoder[Elem comparable](w io.Writer, opts StreamOptions[Elem]) *Encoder[Elem] {
+	e := &Encoder[Elem]{w: bufio.NewWriter(w)}
+	switch opts.Format {
+	case Binary:
+		if opts.Codec == nil {
+			e.err = errors.New("set: StreamOptions.Codec is nil")
+			return e
+		}
+		e.checksum = true
+		e.buf = append(e.buf, streamMagic...)
+		var payload []byte
+		e.encode = func(v Elem) {
+			var err error
+			payload, err = opts.Codec.Append(payload[:0], v)
+			if err != nil {
+				e.err = err
+				return
+			}
+			e.buf = binary.AppendUvarint(e.buf, uint64(len(payload))+1)
+			e.buf = append(e.buf, payload...)
+		}
+		e.end = func() {
+			e.buf = append(e.buf, 0)
+		}
+	case JSONLines, JSONArray:
+		array := opts.Format == JSONArray
+		if array {
+			e.buf = append(e.buf, '[')
+		}
+		e.encode = func(v Elem) {
+			j, err := json.Marshal(v)
+			if err != nil {
+				e.err = err
+				return
+			}
+			if array && e.count > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			e.buf = append(e.buf, j...)
+			if !array {
+				e.buf = append(e.buf, '\n')
+			}
+		}
+		e.end = func() {
+			if array {
+				e.buf = append(e.buf, "]\n"...)
+			}
+		}
+	default:
+		e.err = fmt.Errorf("set: unknown stream format %d", opts.Format)
+	}
+	return e
+}
+
+// NewDeltaEncoder returns an encoder for integer elements that writes
+// each element as the varint of its difference from the previous one,
+// followed by a count and checksum like the Binary format.
+// The stream is smallest when the elements are encoded in order, as
+// from a SliceSet or MappedSet.
+func NewDeltaEncoder[Elem constraints.Integer](w io.Writer) *Encoder[Elem] {
+	e := &Encoder[Elem]{w: bufio.NewWriter(w), checksum: true}
+	e.buf = append(e.buf, streamDeltaMagic...)
+	var prev Elem
+	var chunk []byte
+	n := 0
+	flush := func() {
+		e.buf = binary.AppendUvarint(e.buf, uint64(n))
+		e.buf = append(e.buf, chunk...)
+		chunk = chunk[:0]
+		n = 0
+	}
+	e.encode = func(v Elem) {
+		// The difference is computed in two's complement, so it
+		// round-trips for every pair of values of the type.
+		chunk = binary.AppendVarint(chunk, int64(v-prev))
+		prev = v
+		if n++; n == deltaChunk {
+			flush()
+		}
+	}
+	e.end = func() {
+		if n > 0 {
+			flush()
+		}
+		flush()
+	}
+	return e
+}
+
+// Encode writes v to the stream.
+func (e *Encoder[Elem]) Encode(v Elem) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.encode(v)
+	if e.err != nil {
+		return e.err
+	}
+	e.count++
+	return e.write()
+}
+
+// EncodeSet writes the elements of s to the stream.
+func (e *Encoder[Elem]) EncodeSet(s Interface[Elem]) error {
+	s.Do(func(v Elem) bool {
+		return e.Encode(v) == nil
+	})
+	return e.err
+}
+
+// write moves the encoded bytes from e.buf to the writer.
+func (e *Encoder[Elem]) write() error {
+	if len(e.buf) == 0 {
+		return nil
+	}
+	if e.checksum {
+		e.crc = crc32.Update(e.crc, crcTable, e.buf)
+	}
+	_, e.err = e.w.Write(e.buf)
+	e.buf = e.buf[:0]
+	return e.err
+}
+
+// Close ends the stream and flushes it to the underlying writer,
+// which it doesn't close.
+func (e *Encoder[Elem]) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.end()
+	if e.checksum {
+		e.buf = binary.AppendUvarint(e.buf, e.count)
+		e.crc = crc32.Update(e.crc, crcTable, e.buf)
+		e.buf = binary.LittleEndian.AppendUint32(e.buf, e.crc)
+		_, e.err = e.w.Write(e.buf)
+		e.buf = e.buf[:0]
+	} else {
+		e.write()
+	}
+	if e.err == nil {
+		e.err = e.w.Flush()
+	}
+	if e.err != nil {
+		return e.err
+	}
+	e.err = errors.New("set: Encoder is closed")
+	return nil
+}
+
+// A Decoder reads set elements from a stream written by an Encoder.
+type Decoder[Elem comparable] struct {
+	r   *bufio.Reader
+	crc uint32
+	// rec holds the bytes of the record being read, for the checksum.
+	rec   []byte
+	count uint64
+	err   error
+	// decode returns the next element, or io.EOF after the last one.
+	decode func() (Elem, error)
+}
+
+// NewDecoder returns a decoder that reads from r in the given format.
+func NewDecoder[Elem comparable](r io.Reader, opts StreamOptions[Elem]) *Decoder[Elem] {
+	d := &Decoder[Elem]{r: bufio.NewReader(r)}
+	switch opts.Format {
+	case Binary:
+		if opts.Codec == nil {
+			d.err = errors.New("set: StreamOptions.Codec is nil")
+			return d
+		}
+		var payload []byte
+		started := false
+		d.decode = func() (Elem, error) {
+			var zero Elem
+			if !started {
+				if err := d.readMagic(streamMagic); err != nil {
+					return zero, err
+				}
+				started = true
+			}
+			n, err := d.readUvarint()
+			if err != nil {
+				return zero, err
+			}
+			if n == 0 {
+				return zero, d.readTrailer()
+			}
+			if n-1 > maxStreamElem {
+				return zero, fmt.Errorf("set: element of %d bytes is too large", n-1)
+			}
+			payload, err = readFull(d.r, payload[:0], int(n-1))
+			if err != nil {
+				return zero, unexpected(err)
+			}
+			d.rec = append(d.rec, payload...)
+			d.sum()
+			return opts.Codec.Decode(payload)
+		}
+	case JSONLines, JSONArray:
+		dec := json.NewDecoder(d.r)
+		started := false
+		d.decode = func() (Elem, error) {
+			var v Elem
+			if !started && opts.Format == JSONArray {
+				if err := expectDelim(dec, '['); err != nil {
+					return v, err
+				}
+			}
+			started = true
+			if !dec.More() {
+				if opts.Format == JSONArray {
+					if err := expectDelim(dec, ']'); err != nil {
+						return v, err
+					}
+				}
+				return v, io.EOF
+			}
+			err := dec.Decode(&v)
+			return v, err
+		}
+	default:
+		d.err = fmt.Errorf("set: unknown stream format %d", opts.Format)
+	}
+	return d
+}
+
+// NewDeltaDecoder returns a decoder for streams written by
+// NewDeltaEncoder.
+func NewDeltaDecoder[Elem constraints.Integer](r io.Reader) *Decoder[Elem] {
+	d := &Decoder[Elem]{r: bufio.NewReader(r)}
+	var prev Elem
+	left := uint64(0)
+	started := false
+	d.decode = func() (Elem, error) {
+		if !started {
+			if err := d.readMagic(streamDeltaMagic); err != nil {
+				return 0, err
+			}
+			started = true
+		}
+		if left == 0 {
+			n, err := d.readUvarint()
+			if err != nil {
+				return 0, err
+			}
+			d.sum()
+			if n == 0 {
+				return 0, d.readTrailer()
+			}
+			left = n
+		}
+		x, err := binary.ReadVarint(d)
+		if err != nil {
+			return 0, unexpected(err)
+		}
+		d.sum()
+		left--
+		prev += Elem(x)
+		return prev, nil
+	}
+	return d
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return unexpected(err)
+	}
+	if tok != want {
+		return fmt.Errorf("set: expected %v in JSON stream, found %v", want, tok)
+	}
+	return nil
+}
+
+// Decode returns the next element of the stream.
+// It returns io.EOF after the last element, once the stream's checksum,
+// if any, has been verified. Elements are returned as they are read, so
+// they are only known to be intact once Decode has returned io.EOF.
+func (d *Decoder[Elem]) Decode() (Elem, error) {
+	if d.err != nil {
+		var zero Elem
+		return zero, d.err
+	}
+	v, err := d.decode()
+	if err != nil {
+		d.err = err
+		return v, err
+	}
+	d.count++
+	return v, nil
+}
+
+// DecodeInto adds every remaining element of the stream to s.
+// Elements are added before the stream's checksum is verified, so if
+// DecodeInto returns an error, such as ErrChecksum, s may already hold
+// damaged elements. When that matters, decode into an empty set and
+// add it to s only if DecodeInto succeeds.
+func (d *Decoder[Elem]) DecodeInto(s *Set[Elem]) error {
+	for {
+		v, err := d.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if v != v {
+			d.err = ErrInvalidEncoding
+			return d.err
+		}
+		s.Add(v)
+	}
+}
+
+// ReadByte reads a byte of the current record, for binary.ReadUvarint.
+func (d *Decoder[Elem]) ReadByte() (byte, error) {
+	b, err := d.r.ReadByte()
+	if err == nil {
+		d.rec = append(d.rec, b)
+	}
+	return b, err
+}
+
+func (d *Decoder[Elem]) readUvarint() (uint64, error) {
+	n, err := binary.ReadUvarint(d)
+	return n, unexpected(err)
+}
+
+// sum adds the current record to the checksum.
+func (d *Decoder[Elem]) sum() {
+	d.crc = crc32.Update(d.crc, crcTable, d.rec)
+	d.rec = d.rec[:0]
+}
+
+func (d *Decoder[Elem]) readMagic(magic string) error {
+	b := make([]byte, len(magic))
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return unexpected(err)
+	}
+	if string(b) != magic {
+		return errors.New("set: not a set stream")
+	}
+	d.rec = append(d.rec, b...)
+	d.sum()
+	return nil
+}
+
+// readTrailer reads the element count and checksum that end a stream,
+// and returns io.EOF if they match.
+func (d *Decoder[Elem]) readTrailer() error {
+	d.sum()
+	count, err := d.readUvarint()
+	if err != nil {
+		return err
+	}
+	d.sum()
+	var b [4]byte
+	if _, err := io.ReadFull(d.r, b[:]); err != nil {
+		return unexpected(err)
+	}
+	if count != d.count || binary.LittleEndian.Uint32(b[:]) != d.crc {
+		return ErrChecksum
+	}
+	return io.EOF
+}
+
+// readFull appends n bytes read from r to b. It grows b as the bytes
+// arrive, so that a corrupt length fails at the end of the input rather
+// than allocating all n bytes up front.
+func readFull(r io.Reader, b []byte, n int) ([]byte, error) {
+	for n > 0 {
+		if len(b) == cap(b) {
+			b = slices.Grow(b, min(n, max(len(b), 512)))
+		}
+		k := min(n, cap(b)-len(b))
+		m, err := io.ReadFull(r, b[len(b):len(b)+k])
+		b = b[:len(b)+m]
+		if err != nil {
+			return b, err
+		}
+		n -= m
+	}
+	return b, nil
+}
+
+// unexpected converts io.EOF in the middle of a stream to
+// io.ErrUnexpectedEOF.
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
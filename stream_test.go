@@ -0,0 +1,296 @@
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/constraints"
+)
+
+func TestStreamRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts StreamOptions[string]
+		s    Set[string]
+	}{
+		"binary":            {StreamOptions[string]{Codec: StringCodec{}}, Of("a", "", "日本語")},
+		"binary empty":      {StreamOptions[string]{Codec: StringCodec{}}, Of[string]()},
+		"json lines":        {StreamOptions[string]{Format: JSONLines}, Of("a", "", "b\nc")},
+		"json lines empty":  {StreamOptions[string]{Format: JSONLines}, Of[string]()},
+		"json array":        {StreamOptions[string]{Format: JSONArray}, Of("a", "", "[]")},
+		"json array empty":  {StreamOptions[string]{Format: JSONArray}, Of[string]()},
+		"json array single": {StreamOptions[string]{Format: JSONArray}, Of("x")},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			enc := NewEncoder(&buf, tt.opts)
+			if err := enc.EncodeSet(&tt.s); err != nil {
+				t.Fatal(err)
+			}
+			if err := enc.Close(); err != nil {
+				t.Fatal(err)
+			}
+			var got Set[string]
+			if err := NewDecoder(&buf, tt.opts).DecodeInto(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.s) {
+				t.Fatalf("got %v, want %v", got.ToSlice(), tt.s.ToSlice())
+			}
+		})
+	}
+}
+
+func TestStreamJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		format StreamFormat
+		want   string
+	}{
+		"lines": {JSONLines, "1\n2\n3\n"},
+		"array": {JSONArray, "[1,2,3]\n"},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			enc := NewEncoder(&buf, StreamOptions[int]{Format: tt.format})
+			s := SliceOf(1, 2, 3)
+			enc.EncodeSet(s)
+			if err := enc.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamDelta(t *testing.T) {
+	t.Parallel()
+
+	t.Run("int64", func(t *testing.T) {
+		t.Parallel()
+		testDeltaRoundTrip[int64](t, 0, -1, math.MaxInt64, math.MinInt64, 5, 5)
+	})
+	t.Run("uint64", func(t *testing.T) {
+		t.Parallel()
+		testDeltaRoundTrip[uint64](t, math.MaxUint64, 0, 1, math.MaxUint64-1)
+	})
+	t.Run("int8", func(t *testing.T) {
+		t.Parallel()
+		testDeltaRoundTrip[int8](t, math.MaxInt8, math.MinInt8, -1, 0)
+	})
+	t.Run("chunks", func(t *testing.T) {
+		t.Parallel()
+		values := make([]uint32, 3*deltaChunk+1)
+		for i := range values {
+			values[i] = uint32(i * 7)
+		}
+		testDeltaRoundTrip(t, values...)
+	})
+	t.Run("compact", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		enc := NewDeltaEncoder[uint64](&buf)
+		for v := uint64(1 << 40); v < 1<<40+1000; v++ {
+			enc.Encode(v)
+		}
+		if err := enc.Close(); err != nil {
+			t.Fatal(err)
+		}
+		// The first value takes 6 bytes and each delta 1.
+		if buf.Len() > len(streamDeltaMagic)+6+1000+8*2+8 {
+			t.Fatalf("got %d bytes, want about one per element", buf.Len())
+		}
+	})
+}
+
+func testDeltaRoundTrip[Elem constraints.Integer](t *testing.T, values ...Elem) {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := NewDeltaEncoder[Elem](&buf)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	dec := NewDeltaDecoder[Elem](&buf)
+	for _, want := range values {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Fatalf("got %v, want %v", err, io.EOF)
+	}
+}
+
+func TestStreamCorrupt(t *testing.T) {
+	t.Parallel()
+
+	encode := func(delta bool) []byte {
+		var buf bytes.Buffer
+		var enc *Encoder[int]
+		if delta {
+			enc = NewDeltaEncoder[int](&buf)
+		} else {
+			enc = NewEncoder(&buf, StreamOptions[int]{Codec: VarintCodec[int]{}})
+		}
+		for i := 0; i < 10; i++ {
+			enc.Encode(i * 3)
+		}
+		enc.Close()
+		return buf.Bytes()
+	}
+	decode := func(delta bool, b []byte) error {
+		var s Set[int]
+		if delta {
+			return NewDeltaDecoder[int](bytes.NewReader(b)).DecodeInto(&s)
+		}
+		return NewDecoder(bytes.NewReader(b), StreamOptions[int]{Codec: VarintCodec[int]{}}).DecodeInto(&s)
+	}
+
+	for _, delta := range []bool{false, true} {
+		b := encode(delta)
+		if err := decode(delta, b); err != nil {
+			t.Fatalf("delta %v: %v", delta, err)
+		}
+		tests := map[string]struct {
+			b    []byte
+			want error
+		}{
+			// The last element, before the terminator, count and checksum.
+			"flipped bit": {
+				flip(b, len(b)-7),
+				ErrChecksum,
+			},
+			"bad checksum": {
+				flip(b, len(b)-1),
+				ErrChecksum,
+			},
+			"truncated": {
+				b[:len(b)-2],
+				io.ErrUnexpectedEOF,
+			},
+			"empty": {
+				nil,
+				io.ErrUnexpectedEOF,
+			},
+		}
+		for name, tt := range tests {
+			if err := decode(delta, tt.b); !errors.Is(err, tt.want) {
+				t.Fatalf("delta %v: %s: got %v, want %v", delta, name, err, tt.want)
+			}
+		}
+	}
+}
+
+func TestStreamHostileLength(t *testing.T) {
+	// Not parallel, so that the allocations counted are the decoder's.
+	header := func(n uint64) []byte {
+		return binary.AppendUvarint([]byte(streamMagic), n)
+	}
+	var s Set[string]
+	opts := StreamOptions[string]{Codec: StringCodec{}}
+	b := append(header(maxStreamElem+1), "short"...)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	err := NewDecoder(bytes.NewReader(b), opts).DecodeInto(&s)
+	runtime.ReadMemStats(&after)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("got %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+	if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+		t.Fatalf("got %d bytes allocated, want at most %d", n, 1<<20)
+	}
+
+	if err := NewDecoder(bytes.NewReader(header(maxStreamElem+2)), opts).DecodeInto(&s); err == nil {
+		t.Fatal("got nil error for an element over the limit")
+	}
+}
+
+// flip returns a copy of b with a bit of b[i] flipped.
+func flip(b []byte, i int) []byte {
+	b = bytes.Clone(b)
+	b[i] ^= 0x10
+	return b
+}
+
+func TestStreamErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func() error{
+		"no codec": func() error {
+			return NewEncoder(io.Discard, StreamOptions[int]{}).Encode(1)
+		},
+		"unknown format": func() error {
+			return NewEncoder(io.Discard, StreamOptions[int]{Format: -1}).Close()
+		},
+		"encode after close": func() error {
+			enc := NewEncoder(io.Discard, StreamOptions[int]{Format: JSONLines})
+			enc.Close()
+			return enc.Encode(1)
+		},
+		"not a stream": func() error {
+			var s Set[string]
+			return NewDecoder(strings.NewReader("GOSETXX1"), StreamOptions[string]{Codec: StringCodec{}}).DecodeInto(&s)
+		},
+		"bad json": func() error {
+			var s Set[int]
+			return NewDecoder(strings.NewReader("1\nx\n"), StreamOptions[int]{Format: JSONLines}).DecodeInto(&s)
+		},
+		"unclosed json array": func() error {
+			var s Set[int]
+			return NewDecoder(strings.NewReader("[1,2"), StreamOptions[int]{Format: JSONArray}).DecodeInto(&s)
+		},
+		"nan": func() error {
+			var buf bytes.Buffer
+			enc := NewEncoder(&buf, StreamOptions[float64]{Codec: nanCodec{}})
+			enc.Encode(0)
+			enc.Close()
+			var s Set[float64]
+			return NewDecoder(&buf, StreamOptions[float64]{Codec: nanCodec{}}).DecodeInto(&s)
+		},
+	}
+	for name, f := range tests {
+		f := f
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if err := f(); err == nil {
+				t.Fatal("got nil error")
+			}
+		})
+	}
+}
+
+// nanCodec decodes every element as NaN.
+type nanCodec struct{}
+
+func (nanCodec) Append(b []byte, v float64) ([]byte, error) {
+	return append(b, 0), nil
+}
+
+func (nanCodec) Decode(b []byte) (float64, error) {
+	return math.NaN(), nil
+}
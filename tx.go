@@ -0,0 +1,246 @@
+package set
+
+import "errors"
+
+// ErrInvalidSavepoint is returned by RollbackTo for a savepoint
+// that is no longer part of the transaction's history.
+var ErrInvalidSavepoint = errors.New("set: invalid savepoint")
+
+// Tx is a transaction on a Set. Changes made through a Tx are applied
+// to the set immediately and recorded in an undo log, so they can be
+// rolled back at a cost proportional to the number of changes.
+// The set must not be modified other than through the Tx until
+// the Tx is committed or rolled back.
+type Tx[Elem comparable] struct {
+	s    *Set[Elem]
+	undo []txStep[Elem]
+	redo []txStep[Elem]
+	// steps is the number of steps recorded, used to number them.
+	steps int
+	done  bool
+}
+
+// txStep records the membership changes made by one operation.
+type txStep[Elem comparable] struct {
+	id      int
+	added   []Elem
+	removed []Elem
+}
+
+// Savepoint identifies a point in the history of a Tx: the step
+// recorded just before it was taken, or 0 for the start of the Tx.
+type Savepoint int
+
+// Begin starts a transaction on s.
+func (s *Set[Elem]) Begin() *Tx[Elem] {
+	s.init()
+	return &Tx[Elem]{s: s}
+}
+
+// Add adds elements to the set.
+func (tx *Tx[Elem]) Add(v ...Elem) {
+	tx.check()
+	var step txStep[Elem]
+	for _, v := range v {
+		if v != v {
+			panic("element in set has to be equal to itself")
+		}
+		if !tx.s.Contains(v) {
+			tx.s.m[v] = struct{}{}
+			step.added = append(step.added, v)
+		}
+	}
+	tx.record(step)
+}
+
+// AddSet adds the elements of set s2 to the set.
+func (tx *Tx[Elem]) AddSet(s2 Interface[Elem]) {
+	tx.check()
+	var step txStep[Elem]
+	s2.Do(func(k Elem) bool {
+		if !tx.s.Contains(k) {
+			tx.s.m[k] = struct{}{}
+			step.added = append(step.added, k)
+		}
+		return true
+	})
+	tx.record(step)
+}
+
+// Remove removes elements from the set.
+// Elements that are not present are ignored.
+func (tx *Tx[Elem]) Remove(v ...Elem) {
+	tx.check()
+	tx.s.notePeak()
+	var step txStep[Elem]
+	for _, v := range v {
+		if tx.s.Contains(v) {
+			delete(tx.s.m, v)
+			step.removed = append(step.removed, v)
+		}
+	}
+	tx.record(step)
+}
+
+// RemoveSet removes the elements of set s2 from the set.
+// Elements present in s2 but not the set are ignored.
+func (tx *Tx[Elem]) RemoveSet(s2 Interface[Elem]) {
+	tx.check()
+	tx.s.notePeak()
+	var step txStep[Elem]
+	s2.Do(func(k Elem) bool {
+		if tx.s.Contains(k) {
+			delete(tx.s.m, k)
+			step.removed = append(step.removed, k)
+		}
+		return true
+	})
+	tx.record(step)
+}
+
+// Retain deletes any elements from the set for which keep returns false.
+func (tx *Tx[Elem]) Retain(keep func(Elem) bool) {
+	tx.check()
+	tx.s.notePeak()
+	var step txStep[Elem]
+	for k := range tx.s.m {
+		if !keep(k) {
+			delete(tx.s.m, k)
+			step.removed = append(step.removed, k)
+		}
+	}
+	tx.record(step)
+}
+
+// Clear removes all elements from the set, leaving it empty.
+func (tx *Tx[Elem]) Clear() {
+	tx.Retain(func(Elem) bool { return false })
+}
+
+// Pop removes and returns an arbitrary element from the set.
+func (tx *Tx[Elem]) Pop() (Elem, bool) {
+	tx.check()
+	v, ok := tx.s.Pop()
+	if ok {
+		tx.record(txStep[Elem]{removed: []Elem{v}})
+	}
+	return v, ok
+}
+
+// Savepoint returns the current point in the history of tx.
+func (tx *Tx[Elem]) Savepoint() Savepoint {
+	tx.check()
+	if len(tx.undo) == 0 {
+		return 0
+	}
+	return Savepoint(tx.undo[len(tx.undo)-1].id)
+}
+
+// RollbackTo undoes every operation made since sp was taken.
+// The undone operations can't be redone.
+// Savepoints taken after sp become invalid.
+func (tx *Tx[Elem]) RollbackTo(sp Savepoint) error {
+	tx.check()
+	tx.s.notePeak()
+	// Step numbers increase through the undo log, so the steps
+	// to undo are the ones numbered after sp.
+	i := len(tx.undo)
+	for i > 0 && tx.undo[i-1].id > int(sp) {
+		i--
+	}
+	if i > 0 && tx.undo[i-1].id != int(sp) || i == 0 && sp != 0 {
+		return ErrInvalidSavepoint
+	}
+	for len(tx.undo) > i {
+		tx.revert(tx.pop())
+	}
+	tx.redo = nil
+	return nil
+}
+
+// Undo undoes the last operation that changed the set
+// and reports whether there was one.
+func (tx *Tx[Elem]) Undo() bool {
+	tx.check()
+	tx.s.notePeak()
+	if len(tx.undo) == 0 {
+		return false
+	}
+	step := tx.pop()
+	tx.revert(step)
+	tx.redo = append(tx.redo, step)
+	return true
+}
+
+// Redo reapplies the last undone operation and reports whether there was one.
+// Any new change made through tx clears the operations available to redo.
+func (tx *Tx[Elem]) Redo() bool {
+	tx.check()
+	tx.s.notePeak()
+	if len(tx.redo) == 0 {
+		return false
+	}
+	step := tx.redo[len(tx.redo)-1]
+	tx.redo = tx.redo[:len(tx.redo)-1]
+	for _, v := range step.removed {
+		delete(tx.s.m, v)
+	}
+	for _, v := range step.added {
+		tx.s.m[v] = struct{}{}
+	}
+	tx.undo = append(tx.undo, step)
+	return true
+}
+
+// Commit ends tx, keeping its changes.
+func (tx *Tx[Elem]) Commit() {
+	tx.check()
+	tx.finish()
+}
+
+// Rollback ends tx, undoing all of its changes.
+func (tx *Tx[Elem]) Rollback() {
+	tx.check()
+	tx.s.notePeak()
+	for len(tx.undo) > 0 {
+		tx.revert(tx.pop())
+	}
+	tx.finish()
+}
+
+func (tx *Tx[Elem]) check() {
+	if tx.done {
+		panic("transaction has already been committed or rolled back")
+	}
+}
+
+func (tx *Tx[Elem]) finish() {
+	tx.done = true
+	tx.undo = nil
+	tx.redo = nil
+}
+
+func (tx *Tx[Elem]) record(step txStep[Elem]) {
+	if len(step.added) == 0 && len(step.removed) == 0 {
+		return
+	}
+	tx.steps++
+	step.id = tx.steps
+	tx.undo = append(tx.undo, step)
+	tx.redo = nil
+}
+
+func (tx *Tx[Elem]) pop() txStep[Elem] {
+	step := tx.undo[len(tx.undo)-1]
+	tx.undo = tx.undo[:len(tx.undo)-1]
+	return step
+}
+
+func (tx *Tx[Elem]) revert(step txStep[Elem]) {
+	for _, v := range step.added {
+		delete(tx.s.m, v)
+	}
+	for _, v := range step.removed {
+		tx.s.m[v] = struct{}{}
+	}
+}
@@ -0,0 +1,173 @@
+package set
+
+import "testing"
+
+func TestTx(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		start Set[int]
+		ops   func(tx *Tx[int])
+		want  Set[int]
+	}{
+		"commit": {
+			start: Of(1, 2),
+			ops: func(tx *Tx[int]) {
+				tx.Add(3)
+				tx.Remove(1)
+				tx.Commit()
+			},
+			want: Of(2, 3),
+		},
+		"rollback": {
+			start: Of(1, 2),
+			ops: func(tx *Tx[int]) {
+				tx.Add(3, 4)
+				add := Of(5)
+				tx.AddSet(&add)
+				tx.Remove(1, 9)
+				remove := Of(2)
+				tx.RemoveSet(&remove)
+				tx.Retain(func(v int) bool { return v != 3 })
+				tx.Pop()
+				tx.Clear()
+				tx.Rollback()
+			},
+			want: Of(1, 2),
+		},
+		"rollback initialization": {
+			start: Set[int]{},
+			ops: func(tx *Tx[int]) {
+				tx.Add(1)
+				tx.Rollback()
+			},
+			want: Of[int](),
+		},
+		"rollback re-added element": {
+			start: Of(1),
+			ops: func(tx *Tx[int]) {
+				tx.Remove(1)
+				tx.Add(1)
+				tx.Rollback()
+			},
+			want: Of(1),
+		},
+		"nested savepoints": {
+			start: Of(1),
+			ops: func(tx *Tx[int]) {
+				tx.Add(2)
+				outer := tx.Savepoint()
+				tx.Add(3)
+				inner := tx.Savepoint()
+				tx.Remove(1)
+				tx.RollbackTo(inner)
+				tx.Add(4)
+				tx.RollbackTo(outer)
+				tx.Add(5)
+				tx.Commit()
+			},
+			want: Of(1, 2, 5),
+		},
+		"undo and redo": {
+			start: Of(1),
+			ops: func(tx *Tx[int]) {
+				tx.Add(2)
+				tx.Add(3)
+				tx.Remove(1)
+				tx.Undo()
+				tx.Undo()
+				tx.Redo()
+				tx.Commit()
+			},
+			want: Of(1, 2, 3),
+		},
+		"new change clears redo": {
+			start: Of(1),
+			ops: func(tx *Tx[int]) {
+				tx.Add(2)
+				tx.Undo()
+				tx.Add(3)
+				tx.Redo()
+				tx.Commit()
+			},
+			want: Of(1, 3),
+		},
+		"no-op does not create history": {
+			start: Of(1),
+			ops: func(tx *Tx[int]) {
+				tx.Add(2)
+				tx.Add(1)
+				tx.Remove(5)
+				tx.Undo()
+				tx.Commit()
+			},
+			want: Of(1),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			tt.ops(tt.start.Begin())
+			if !tt.want.Equal(tt.start) {
+				t.Fatalf("got %v, want %v", tt.start.ToSlice(), tt.want.ToSlice())
+			}
+		})
+	}
+}
+
+func TestTxInvalidSavepoint(t *testing.T) {
+	t.Parallel()
+
+	s := Of(1)
+	tx := s.Begin()
+	tx.Add(2)
+	sp := tx.Savepoint()
+	tx.Undo()
+	if err := tx.RollbackTo(sp); err != ErrInvalidSavepoint {
+		t.Fatalf("got %v, want %v", err, ErrInvalidSavepoint)
+	}
+	// New changes don't make the undone savepoint valid again.
+	tx.Add(3)
+	if err := tx.RollbackTo(sp); err != ErrInvalidSavepoint {
+		t.Fatalf("got %v, want %v", err, ErrInvalidSavepoint)
+	}
+	if err := tx.RollbackTo(-1); err != ErrInvalidSavepoint {
+		t.Fatalf("got %v, want %v", err, ErrInvalidSavepoint)
+	}
+	if want := Of(1, 3); !s.Equal(want) {
+		t.Fatalf("got %v, want %v", s, want)
+	}
+
+	// Nor does redoing changes after a rollback.
+	sp = tx.Savepoint()
+	tx.Add(4)
+	sp2 := tx.Savepoint()
+	if err := tx.RollbackTo(sp); err != nil {
+		t.Fatal(err)
+	}
+	tx.Add(5)
+	if err := tx.RollbackTo(sp2); err != ErrInvalidSavepoint {
+		t.Fatalf("got %v, want %v", err, ErrInvalidSavepoint)
+	}
+	if err := tx.RollbackTo(0); err != nil {
+		t.Fatal(err)
+	}
+	if want := Of(1); !s.Equal(want) {
+		t.Fatalf("got %v, want %v", s, want)
+	}
+}
+
+func TestTxUseAfterCommit(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "transaction has already been committed or rolled back" {
+			t.Fatalf("got %v, want %v", r, "transaction has already been committed or rolled back")
+		}
+	}()
+	s := Of(1)
+	tx := s.Begin()
+	tx.Commit()
+	tx.Add(2)
+}